@@ -0,0 +1,127 @@
+package nvml
+
+import (
+	"fmt"
+	"time"
+)
+
+// Comparator compares a reading against an AlertRule's threshold
+type Comparator int
+
+const (
+	GreaterThan Comparator = iota
+	GreaterOrEqual
+	LessThan
+	LessOrEqual
+)
+
+func (c Comparator) String() string {
+	switch c {
+	case GreaterThan:
+		return ">"
+	case GreaterOrEqual:
+		return ">="
+	case LessThan:
+		return "<"
+	case LessOrEqual:
+		return "<="
+	}
+
+	return fmt.Sprintf("Comparator(%d)", int(c))
+}
+
+func (c Comparator) compare(value, threshold Value) bool {
+	switch c {
+	case GreaterThan:
+		return value > threshold
+	case GreaterOrEqual:
+		return value >= threshold
+	case LessThan:
+		return value < threshold
+	case LessOrEqual:
+		return value <= threshold
+	}
+
+	return false
+}
+
+// AlertRule fires when a metric of a device has compared true against the
+// threshold for at least Duration, e.g. PropertyTemperature GreaterThan 85 for
+// 30 seconds
+type AlertRule struct {
+	Name       string
+	Metric     Property
+	Comparator Comparator
+	Threshold  Value
+	Duration   time.Duration
+}
+
+func (r AlertRule) String() string {
+	return fmt.Sprintf("%s %s %d for %s", r.Metric, r.Comparator, uint64(r.Threshold), r.Duration)
+}
+
+// Alert is emitted when a rule starts firing for a device, and again with
+// Resolved set once the condition no longer holds
+type Alert struct {
+	Rule     AlertRule
+	Device   *Device
+	Value    Value     // the reading that changed the alert's state
+	Since    time.Time // when the condition started to hold
+	Time     time.Time
+	Resolved bool
+}
+
+type alertKey struct {
+	device *Device
+	rule   int
+}
+
+type alertState struct {
+	since  time.Time
+	firing bool
+}
+
+// WatchAlerts evaluates the rules against the readings, typically those of a
+// Monitor, and sends alerts on the returned channel. The channel is closed
+// once readings is.
+func WatchAlerts(readings <-chan Reading, rules ...AlertRule) <-chan Alert {
+	alerts := make(chan Alert)
+
+	go func() {
+		defer close(alerts)
+
+		states := make(map[alertKey]*alertState)
+		for reading := range readings {
+			for i, rule := range rules {
+				if rule.Metric != reading.Property {
+					continue
+				}
+
+				key := alertKey{reading.Device, i}
+				state := states[key]
+				breached := rule.Comparator.compare(reading.Value, rule.Threshold)
+
+				switch {
+				case breached && state == nil:
+					state = &alertState{since: reading.Time}
+					states[key] = state
+				case !breached && state != nil:
+					delete(states, key)
+					if state.firing {
+						alerts <- Alert{Rule: rule, Device: reading.Device, Value: reading.Value, Since: state.since, Time: reading.Time, Resolved: true}
+					}
+					continue
+				case !breached:
+					continue
+				}
+
+				if !state.firing && reading.Time.Sub(state.since) >= rule.Duration {
+					state.firing = true
+					alerts <- Alert{Rule: rule, Device: reading.Device, Value: reading.Value, Since: state.since, Time: reading.Time}
+				}
+			}
+		}
+	}()
+
+	return alerts
+}
@@ -0,0 +1,53 @@
+package nvml
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchAlerts(t *testing.T) {
+	gpu := &Device{}
+	start := time.Unix(0, 0)
+	rule := AlertRule{Metric: PropertyTemperature, Comparator: GreaterThan, Threshold: 85, Duration: 30 * time.Second}
+
+	readings := make(chan Reading)
+	alerts := WatchAlerts(readings, rule)
+
+	go func() {
+		for i, temp := range []Value{80, 90, 90, 90, 90, 70, 90} {
+			readings <- Reading{Device: gpu, Property: PropertyTemperature, Value: temp, Time: start.Add(time.Duration(i) * 10 * time.Second)}
+			readings <- Reading{Device: gpu, Property: PropertyPowerUsage, Value: 1000, Time: start}
+		}
+		close(readings)
+	}()
+
+	var got []Alert
+	for alert := range alerts {
+		got = append(got, alert)
+	}
+
+	var tests = []struct {
+		resolved bool
+		since    time.Duration
+		at       time.Duration
+	}{
+		{false, 10 * time.Second, 40 * time.Second},
+		{true, 10 * time.Second, 50 * time.Second},
+	}
+
+	if len(got) != len(tests) {
+		t.Fatalf("got %d alerts, expected %d: %v", len(got), len(tests), got)
+	}
+	for i, ts := range tests {
+		if got[i].Resolved != ts.resolved || !got[i].Since.Equal(start.Add(ts.since)) || !got[i].Time.Equal(start.Add(ts.at)) {
+			t.Errorf("alert %d = %+v, expected resolved=%v since=%s at=%s", i, got[i], ts.resolved, ts.since, ts.at)
+		}
+	}
+}
+
+func TestAlertRuleString(t *testing.T) {
+	rule := AlertRule{Metric: PropertyMemoryFree, Comparator: LessThan, Threshold: 1 << 30, Duration: time.Minute}
+	if rule.String() != "MemoryFree < 1073741824 for 1m0s" {
+		t.Errorf("AlertRule.String() = %s", rule.String())
+	}
+}
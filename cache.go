@@ -0,0 +1,169 @@
+package nvml
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CachedDevice wraps a Device so that several consumers in one process can
+// query it without multiplying the load on the driver. Static properties are
+// memoized until Invalidate is called; dynamic metrics are memoized for the
+// TTL. Errors are never cached. Methods not overridden here go straight to the
+// Device.
+type CachedDevice struct {
+	*Device
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time // zero for static properties
+}
+
+// NewCachedDevice wraps gpu, caching its dynamic metrics for ttl
+func NewCachedDevice(gpu *Device, ttl time.Duration) *CachedDevice {
+	return &CachedDevice{
+		Device:  gpu,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Invalidate drops all cached values, static ones included. Call it after
+// refreshing the underlying Device.
+func (c *CachedDevice) Invalidate() {
+	c.mu.Lock()
+	c.entries = make(map[string]cacheEntry)
+	c.mu.Unlock()
+}
+
+// cached returns the cached value for key, or calls f and caches its result,
+// forever if static is set and for the TTL otherwise. Concurrent misses for the
+// same key may each call f.
+func cached[T any](c *CachedDevice, key string, static bool, f func() (T, error)) (T, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && (entry.expires.IsZero() || c.now().Before(entry.expires)) {
+		return entry.value.(T), nil
+	}
+
+	value, err := f()
+	if err != nil {
+		return value, err
+	}
+
+	entry = cacheEntry{value: value}
+	if !static {
+		entry.expires = c.now().Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// pair holds the results of methods that return two values
+type pair[A, B any] struct {
+	a A
+	b B
+}
+
+// UUID returns the device UUID, cached until Invalidate
+func (c *CachedDevice) UUID() (string, error) {
+	return cached(c, "UUID", true, c.Device.UUID)
+}
+
+// Name returns the product name, cached until Invalidate
+func (c *CachedDevice) Name() (string, error) {
+	return cached(c, "Name", true, c.Device.Name)
+}
+
+// Serial returns the board serial number, cached until Invalidate
+func (c *CachedDevice) Serial() (string, error) {
+	return cached(c, "Serial", true, c.Device.Serial)
+}
+
+// VbiosVersion returns the VBIOS version, cached until Invalidate
+func (c *CachedDevice) VbiosVersion() (string, error) {
+	return cached(c, "VbiosVersion", true, c.Device.VbiosVersion)
+}
+
+// Brand returns the brand of the device, cached until Invalidate
+func (c *CachedDevice) Brand() (Brand, error) {
+	return cached(c, "Brand", true, c.Device.Brand)
+}
+
+// Index returns the NVML index of the device, cached until Invalidate
+func (c *CachedDevice) Index() (uint, error) {
+	return cached(c, "Index", true, c.Device.Index)
+}
+
+// MinorNumber returns the minor number of the device node, cached until
+// Invalidate
+func (c *CachedDevice) MinorNumber() (uint, error) {
+	return cached(c, "MinorNumber", true, c.Device.MinorNumber)
+}
+
+// PciInfo returns the PCI attributes of the device, cached until Invalidate
+func (c *CachedDevice) PciInfo() (PciInfo, error) {
+	return cached(c, "PciInfo", true, c.Device.PciInfo)
+}
+
+// PerformanceState returns the current performance state, cached for the TTL
+func (c *CachedDevice) PerformanceState() (Pstate, error) {
+	return cached(c, "PerformanceState", false, c.Device.PerformanceState)
+}
+
+// Temperature returns the reading of the given sensor, cached for the TTL
+func (c *CachedDevice) Temperature(sensor TemperatureSensor) (uint, error) {
+	return cached(c, fmt.Sprintf("Temperature/%d", int(sensor)), false, func() (uint, error) {
+		return c.Device.Temperature(sensor)
+	})
+}
+
+// Temp returns the GPU core temperature, cached for the TTL
+func (c *CachedDevice) Temp() (uint, error) {
+	return c.Temperature(TemperatureGPU)
+}
+
+// FanSpeed returns the fan speed in percent, cached for the TTL
+func (c *CachedDevice) FanSpeed() (uint, error) {
+	return cached(c, "FanSpeed", false, c.Device.FanSpeed)
+}
+
+// PowerUsage returns the power draw in milliwatts, cached for the TTL
+func (c *CachedDevice) PowerUsage() (uint, error) {
+	return cached(c, "PowerUsage", false, c.Device.PowerUsage)
+}
+
+// EnforcedPowerLimit returns the effective power limit in milliwatts, cached
+// for the TTL
+func (c *CachedDevice) EnforcedPowerLimit() (uint, error) {
+	return cached(c, "EnforcedPowerLimit", false, c.Device.EnforcedPowerLimit)
+}
+
+// MemoryInfo returns the framebuffer memory usage, cached for the TTL
+func (c *CachedDevice) MemoryInfo() (NVMLMemory, error) {
+	return cached(c, "MemoryInfo", false, c.Device.MemoryInfo)
+}
+
+// GetUtilizationRates returns the GPU and memory utilization, cached for the
+// TTL
+func (c *CachedDevice) GetUtilizationRates() (gpuUtilization uint, memoryUtilization uint, err error) {
+	p, err := cached(c, "UtilizationRates", false, func() (pair[uint, uint], error) {
+		gpu, mem, err := c.Device.GetUtilizationRates()
+		return pair[uint, uint]{gpu, mem}, err
+	})
+
+	return p.a, p.b, err
+}
@@ -0,0 +1,60 @@
+package nvml
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCached(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := NewCachedDevice(nil, time.Second)
+	c.now = func() time.Time { return now }
+
+	var calls int
+	f := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	var tests = []struct {
+		advance time.Duration
+		static  bool
+		value   int
+	}{
+		{0, false, 1},
+		{500 * time.Millisecond, false, 1},
+		{time.Second, false, 2},
+		{0, true, 3},
+		{time.Hour, true, 3},
+	}
+
+	for i, ts := range tests {
+		now = now.Add(ts.advance)
+
+		key := "dynamic"
+		if ts.static {
+			key = "static"
+		}
+
+		value, err := cached(c, key, ts.static, f)
+		if err != nil || value != ts.value {
+			t.Errorf("%d: cached() = %d, %v, expected %d", i, value, err, ts.value)
+		}
+	}
+
+	c.Invalidate()
+	if value, _ := cached(c, "static", true, f); value != 4 {
+		t.Errorf("cached() after Invalidate = %d, expected 4", value)
+	}
+
+	failing := func() (int, error) {
+		calls++
+		return 0, errors.New("failed")
+	}
+	cached(c, "failing", false, failing)
+	cached(c, "failing", false, failing)
+	if calls != 6 {
+		t.Errorf("errors were cached")
+	}
+}
@@ -0,0 +1,174 @@
+// Command gonvml is a small nvidia-smi work-alike built on the nvml package.
+//
+// Usage:
+//
+//	gonvml                  print a table of all devices
+//	gonvml -l 5             print the table every 5 seconds
+//	gonvml -json            dump snapshots of all devices as JSON
+//	gonvml -i 0 -pl 250     set the power limit of device 0 to 250 W
+//	gonvml -i 0 -pm 1       enable persistence mode on device 0
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	nvml "github.com/davidr/go-nvml"
+)
+
+var (
+	loop        = flag.Uint("l", 0, "repeat every `seconds`")
+	asJSON      = flag.Bool("json", false, "print JSON snapshots instead of a table")
+	index       = flag.Int("i", -1, "only act on the device with this `index`")
+	powerLimit  = flag.Uint("pl", 0, "set the power limit, in `watts`")
+	persistence = flag.Int("pm", -1, "set persistence mode (0 or 1)")
+)
+
+func main() {
+	flag.Parse()
+
+	if err := nvml.NVMLInit(); err != nil {
+		fatal(err)
+	}
+	defer nvml.NVMLShutdown()
+
+	devices, err := selectDevices()
+	if err != nil {
+		fatal(err)
+	}
+
+	if *powerLimit != 0 || *persistence != -1 {
+		if err := apply(devices); err != nil {
+			fatal(err)
+		}
+		return
+	}
+
+	for {
+		if *asJSON {
+			err = printJSON(devices)
+		} else {
+			err = printTable(devices)
+		}
+		if err != nil {
+			fatal(err)
+		}
+
+		if *loop == 0 {
+			return
+		}
+		time.Sleep(time.Duration(*loop) * time.Second)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "gonvml:", err)
+	os.Exit(1)
+}
+
+// selectDevices returns all devices, or only the one selected with -i
+func selectDevices() ([]*nvml.Device, error) {
+	devices, err := nvml.GetAllGPUs()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gonvml:", err)
+	}
+	if *index < 0 {
+		return devices, nil
+	}
+
+	for _, gpu := range devices {
+		if i, err := gpu.Index(); err == nil && int(i) == *index {
+			return []*nvml.Device{gpu}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no device with index %d", *index)
+}
+
+// apply applies the settings given on the command line to the devices
+func apply(devices []*nvml.Device) error {
+	var errs []error
+
+	for _, gpu := range devices {
+		name, _ := gpu.Name()
+
+		if *powerLimit != 0 {
+			if err := gpu.SetPowerManagementLimit(*powerLimit * 1000); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			} else {
+				fmt.Printf("%s: power limit set to %d W\n", name, *powerLimit)
+			}
+		}
+
+		if *persistence != -1 {
+			if err := gpu.SetPersistenceMode(*persistence != 0); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			} else {
+				fmt.Printf("%s: persistence mode set to %d\n", name, *persistence)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func printJSON(devices []*nvml.Device) error {
+	var snapshots []nvml.DeviceSnapshot
+
+	for _, gpu := range devices {
+		snapshot, err := gpu.Snapshot()
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(snapshots)
+}
+
+// optional formats a metric that may not be supported
+func optional[T any](v *T, format string) string {
+	if v == nil {
+		return "N/A"
+	}
+
+	return fmt.Sprintf(format, *v)
+}
+
+func printTable(devices []*nvml.Device) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "IDX\tNAME\tBUS-ID\tPERF\tTEMP\tPOWER\tMEMORY\tGPU-UTIL\tPROCS")
+
+	for _, gpu := range devices {
+		s, err := gpu.Snapshot()
+		if err != nil {
+			return err
+		}
+
+		power := "N/A"
+		if s.PowerUsage != nil && s.PowerLimit != nil {
+			power = fmt.Sprintf("%dW / %dW", *s.PowerUsage/1000, *s.PowerLimit/1000)
+		}
+		memory := "N/A"
+		if s.MemoryUsed != nil && s.MemoryTotal != nil {
+			memory = fmt.Sprintf("%dMiB / %dMiB", *s.MemoryUsed>>20, *s.MemoryTotal>>20)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+			s.Index, s.Name, s.PciBusID,
+			optional(s.PerformanceState, "%s"),
+			optional(s.Temperature, "%dC"),
+			power, memory,
+			optional(s.UtilizationGPU, "%d%%"),
+			len(s.Processes))
+	}
+
+	return w.Flush()
+}
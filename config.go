@@ -0,0 +1,265 @@
+package nvml
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ConfigDifference describes a single configuration setting that differs
+// between two devices. A is the value on the first device and B the value on
+// the second; a nil value means the setting couldn't be read on that device.
+type ConfigDifference struct {
+	Field string
+	A     interface{}
+	B     interface{}
+}
+
+func (d ConfigDifference) String() string {
+	return fmt.Sprintf("%s: %v != %v", d.Field, d.A, d.B)
+}
+
+// configField is a setting compared by CompareConfig
+type configField struct {
+	name string
+	get  func(gpu *Device) (interface{}, error)
+}
+
+// configFields lists the settings compared by CompareConfig, in the order the
+// differences are reported.
+var configFields = []configField{
+	{"ApplicationsClock(Graphics)", func(gpu *Device) (interface{}, error) { return gpu.ApplicationsClock(ClockGraphics) }},
+	{"ApplicationsClock(Mem)", func(gpu *Device) (interface{}, error) { return gpu.ApplicationsClock(ClockMem) }},
+	{"EccMode", func(gpu *Device) (interface{}, error) {
+		current, _, err := gpu.EccMode()
+		return current, err
+	}},
+	{"PowerManagementLimit", func(gpu *Device) (interface{}, error) { return gpu.PowerManagementLimit() }},
+	{"ComputeMode", func(gpu *Device) (interface{}, error) { return gpu.ComputeMode() }},
+	{"VbiosVersion", func(gpu *Device) (interface{}, error) { return gpu.VbiosVersion() }},
+	{"InforomImageVersion", func(gpu *Device) (interface{}, error) { return gpu.InforomImageVersion() }},
+}
+
+// CompareConfig compares the configuration of two devices (application clocks,
+// ECC mode, power limit, compute mode and firmware versions) and returns the
+// settings that differ. An empty result means the devices are configured
+// identically. Settings that can't be read on either device, e.g. ECC on
+// boards without ECC memory, are skipped.
+func CompareConfig(a, b *Device) ([]ConfigDifference, error) {
+	var diffs []ConfigDifference
+
+	if a == nil || b == nil {
+		return diffs, errors.New("CompareConfig requires two devices")
+	}
+
+	for _, field := range configFields {
+		va, erra := field.get(a)
+		vb, errb := field.get(b)
+
+		if erra != nil && errb != nil {
+			continue
+		}
+		if erra != nil {
+			va = nil
+		}
+		if errb != nil {
+			vb = nil
+		}
+
+		if va != vb {
+			diffs = append(diffs, ConfigDifference{Field: field.name, A: va, B: vb})
+		}
+	}
+
+	return diffs, nil
+}
+
+// ApplicationsClocks is a pair of applications clocks, in MHz
+type ApplicationsClocks struct {
+	Mem      uint `json:"mem"`
+	Graphics uint `json:"graphics"`
+}
+
+// DeviceConfig holds the settable configuration of a device. A nil field
+// means the setting isn't supported by the device (when exported) or should
+// be left untouched (when applied). For ECC and GOM, which only change after
+// a reboot, the pending value is used. The Restricted fields are the API
+// restrictions of the RestrictedAPI groups; ApplyConfig sets them last, so
+// that restricting an API doesn't keep the other settings from being applied.
+type DeviceConfig struct {
+	PowerManagementLimit           *uint               `json:"power_management_limit,omitempty"`
+	EccEnabled                     *bool               `json:"ecc_enabled,omitempty"`
+	ComputeMode                    *ComputeMode        `json:"compute_mode,omitempty"`
+	GpuOperationMode               *GpuOperationMode   `json:"gpu_operation_mode,omitempty"`
+	PersistenceMode                *bool               `json:"persistence_mode,omitempty"`
+	ApplicationsClocks             *ApplicationsClocks `json:"applications_clocks,omitempty"`
+	AutoBoostedClocksEnabled       *bool               `json:"auto_boosted_clocks_enabled,omitempty"`
+	AccountingMode                 *bool               `json:"accounting_mode,omitempty"`
+	SetApplicationClocksRestricted *bool               `json:"set_application_clocks_restricted,omitempty"`
+	SetAutoBoostedClocksRestricted *bool               `json:"set_auto_boosted_clocks_restricted,omitempty"`
+}
+
+// ConfigResult is the outcome of applying a single setting of a DeviceConfig.
+// Changed reports whether the setting was changed, or in a dry run whether it
+// would have been.
+type ConfigResult struct {
+	Field   string
+	Current interface{}
+	Desired interface{}
+	Changed bool
+	Err     error
+}
+
+// ExportConfig reads all settable configuration of the device. Settings the
+// device doesn't support are left nil. An error is only returned if nothing
+// could be read at all.
+func (gpu *Device) ExportConfig() (DeviceConfig, error) {
+	var cfg DeviceConfig
+	var found int
+
+	if limit, err := gpu.PowerManagementLimit(); err == nil {
+		cfg.PowerManagementLimit = &limit
+		found++
+	}
+	if _, pending, err := gpu.EccMode(); err == nil {
+		cfg.EccEnabled = &pending
+		found++
+	}
+	if mode, err := gpu.ComputeMode(); err == nil {
+		cfg.ComputeMode = &mode
+		found++
+	}
+	if _, pending, err := gpu.GpuOperationMode(); err == nil {
+		cfg.GpuOperationMode = &pending
+		found++
+	}
+	if mode, err := gpu.PersistenceMode(); err == nil {
+		cfg.PersistenceMode = &mode
+		found++
+	}
+	mem, errm := gpu.ApplicationsClock(ClockMem)
+	graphics, errg := gpu.ApplicationsClock(ClockGraphics)
+	if errm == nil && errg == nil {
+		cfg.ApplicationsClocks = &ApplicationsClocks{Mem: mem, Graphics: graphics}
+		found++
+	}
+	if enabled, _, err := gpu.AutoBoostedClocksEnabled(); err == nil {
+		cfg.AutoBoostedClocksEnabled = &enabled
+		found++
+	}
+	if mode, err := gpu.AccountingMode(); err == nil {
+		cfg.AccountingMode = &mode
+		found++
+	}
+	if restricted, err := gpu.APIRestriction(RestrictedAPISetApplicationClocks); err == nil {
+		cfg.SetApplicationClocksRestricted = &restricted
+		found++
+	}
+	if restricted, err := gpu.APIRestriction(RestrictedAPISetAutoBoostedClocks); err == nil {
+		cfg.SetAutoBoostedClocksRestricted = &restricted
+		found++
+	}
+
+	if found == 0 {
+		return cfg, errors.New("ExportConfig could not read any setting")
+	}
+
+	return cfg, nil
+}
+
+// ApplyConfig changes every non-nil setting of cfg that differs from the
+// device's current configuration, and returns a result for each of them. If
+// dryRun is true nothing is changed, and the results report what would be.
+// Most settings require root. The returned error is non-nil if any setting
+// couldn't be read or applied; the results say which.
+func (gpu *Device) ApplyConfig(cfg DeviceConfig, dryRun bool) ([]ConfigResult, error) {
+	var results []ConfigResult
+	var failed int
+
+	apply := func(field string, current interface{}, err error, desired interface{}, set func() error) {
+		res := ConfigResult{Field: field, Current: current, Desired: desired, Err: err}
+		if err == nil && current != desired {
+			res.Changed = true
+			if !dryRun {
+				res.Err = set()
+			}
+		}
+		if res.Err != nil {
+			res.Changed = false
+			failed++
+		}
+		results = append(results, res)
+	}
+
+	if cfg.PowerManagementLimit != nil {
+		limit, err := gpu.PowerManagementLimit()
+		apply("PowerManagementLimit", limit, err, *cfg.PowerManagementLimit, func() error {
+			return gpu.SetPowerManagementLimit(*cfg.PowerManagementLimit)
+		})
+	}
+	if cfg.EccEnabled != nil {
+		_, pending, err := gpu.EccMode()
+		apply("EccEnabled", pending, err, *cfg.EccEnabled, func() error {
+			return gpu.SetEccMode(*cfg.EccEnabled)
+		})
+	}
+	if cfg.ComputeMode != nil {
+		mode, err := gpu.ComputeMode()
+		apply("ComputeMode", mode, err, *cfg.ComputeMode, func() error {
+			return gpu.SetComputeMode(*cfg.ComputeMode)
+		})
+	}
+	if cfg.GpuOperationMode != nil {
+		_, pending, err := gpu.GpuOperationMode()
+		apply("GpuOperationMode", pending, err, *cfg.GpuOperationMode, func() error {
+			_, err := gpu.SetGpuOperationMode(*cfg.GpuOperationMode)
+			return err
+		})
+	}
+	if cfg.PersistenceMode != nil {
+		mode, err := gpu.PersistenceMode()
+		apply("PersistenceMode", mode, err, *cfg.PersistenceMode, func() error {
+			return gpu.SetPersistenceMode(*cfg.PersistenceMode)
+		})
+	}
+	if cfg.ApplicationsClocks != nil {
+		var current ApplicationsClocks
+		var err error
+		current.Mem, err = gpu.ApplicationsClock(ClockMem)
+		if err == nil {
+			current.Graphics, err = gpu.ApplicationsClock(ClockGraphics)
+		}
+		apply("ApplicationsClocks", current, err, *cfg.ApplicationsClocks, func() error {
+			return gpu.SetApplicationsClocks(cfg.ApplicationsClocks.Mem, cfg.ApplicationsClocks.Graphics)
+		})
+	}
+	if cfg.AutoBoostedClocksEnabled != nil {
+		enabled, _, err := gpu.AutoBoostedClocksEnabled()
+		apply("AutoBoostedClocksEnabled", enabled, err, *cfg.AutoBoostedClocksEnabled, func() error {
+			return gpu.SetAutoBoostedClocksEnabled(*cfg.AutoBoostedClocksEnabled)
+		})
+	}
+	if cfg.AccountingMode != nil {
+		mode, err := gpu.AccountingMode()
+		apply("AccountingMode", mode, err, *cfg.AccountingMode, func() error {
+			return gpu.SetAccountingMode(*cfg.AccountingMode)
+		})
+	}
+	if cfg.SetApplicationClocksRestricted != nil {
+		restricted, err := gpu.APIRestriction(RestrictedAPISetApplicationClocks)
+		apply("SetApplicationClocksRestricted", restricted, err, *cfg.SetApplicationClocksRestricted, func() error {
+			return gpu.SetAPIRestriction(RestrictedAPISetApplicationClocks, *cfg.SetApplicationClocksRestricted)
+		})
+	}
+	if cfg.SetAutoBoostedClocksRestricted != nil {
+		restricted, err := gpu.APIRestriction(RestrictedAPISetAutoBoostedClocks)
+		apply("SetAutoBoostedClocksRestricted", restricted, err, *cfg.SetAutoBoostedClocksRestricted, func() error {
+			return gpu.SetAPIRestriction(RestrictedAPISetAutoBoostedClocks, *cfg.SetAutoBoostedClocksRestricted)
+		})
+	}
+
+	if failed > 0 {
+		return results, fmt.Errorf("ApplyConfig: %d of %d settings failed", failed, len(results))
+	}
+
+	return results, nil
+}
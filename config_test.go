@@ -0,0 +1,124 @@
+package nvml
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompareConfig(t *testing.T) {
+	devices := unreachableDevices(t, 2)
+	a, b := devices[0], devices[1]
+	errUnreadable := errors.New("unreadable")
+
+	// setting returns a config field whose value on each device is given by
+	// values, and which fails on devices missing from it
+	setting := func(name string, values map[*Device]interface{}) configField {
+		return configField{name, func(gpu *Device) (interface{}, error) {
+			if v, ok := values[gpu]; ok {
+				return v, nil
+			}
+			return nil, errUnreadable
+		}}
+	}
+
+	saved := configFields
+	defer func() { configFields = saved }()
+	configFields = []configField{
+		setting("Same", map[*Device]interface{}{a: uint(100), b: uint(100)}),
+		setting("Different", map[*Device]interface{}{a: ComputeModeDefault, b: ComputeModeProhibited}),
+		setting("OnlyA", map[*Device]interface{}{a: true}),
+		setting("OnlyB", map[*Device]interface{}{b: "86.00"}),
+		setting("Neither", nil),
+	}
+
+	diffs, err := CompareConfig(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ConfigDifference{
+		{"Different", ComputeModeDefault, ComputeModeProhibited},
+		{"OnlyA", true, nil},
+		{"OnlyB", nil, "86.00"},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("CompareConfig = %v, expected %v", diffs, expected)
+	}
+
+	if diffs, err := CompareConfig(a, a); err != nil || len(diffs) != 0 {
+		t.Errorf("CompareConfig of a device with itself = %v, %v", diffs, err)
+	}
+	if _, err := CompareConfig(a, nil); err == nil {
+		t.Errorf("CompareConfig with a nil device succeeded")
+	}
+}
+
+func TestExportConfigUnsupported(t *testing.T) {
+	cfg, err := unreachableDevices(t, 1)[0].ExportConfig()
+	if err == nil {
+		t.Errorf("ExportConfig of an unreachable device succeeded")
+	}
+	if !reflect.DeepEqual(cfg, DeviceConfig{}) {
+		t.Errorf("ExportConfig of an unreachable device = %+v, expected all settings nil", cfg)
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	limit := uint(200000)
+	enabled := true
+	mode := ComputeModeExclusiveProcess
+	gom := GomCompute
+
+	var tests = []struct {
+		cfg    DeviceConfig
+		fields []string
+	}{
+		{DeviceConfig{}, nil},
+		{
+			DeviceConfig{PowerManagementLimit: &limit, ComputeMode: &mode},
+			[]string{"PowerManagementLimit", "ComputeMode"},
+		},
+		{
+			DeviceConfig{
+				PowerManagementLimit:           &limit,
+				EccEnabled:                     &enabled,
+				ComputeMode:                    &mode,
+				GpuOperationMode:               &gom,
+				PersistenceMode:                &enabled,
+				ApplicationsClocks:             &ApplicationsClocks{Mem: 5001, Graphics: 1590},
+				AutoBoostedClocksEnabled:       &enabled,
+				AccountingMode:                 &enabled,
+				SetApplicationClocksRestricted: &enabled,
+				SetAutoBoostedClocksRestricted: &enabled,
+			},
+			[]string{
+				"PowerManagementLimit", "EccEnabled", "ComputeMode", "GpuOperationMode",
+				"PersistenceMode", "ApplicationsClocks", "AutoBoostedClocksEnabled", "AccountingMode",
+				"SetApplicationClocksRestricted", "SetAutoBoostedClocksRestricted",
+			},
+		},
+	}
+
+	gpu := unreachableDevices(t, 1)[0]
+	for _, ts := range tests {
+		for _, dryRun := range []bool{true, false} {
+			results, err := gpu.ApplyConfig(ts.cfg, dryRun)
+
+			var fields []string
+			for _, res := range results {
+				fields = append(fields, res.Field)
+				if res.Err == nil || res.Changed {
+					t.Errorf("%s: unreadable setting reported as %+v", res.Field, res)
+				}
+			}
+			if !reflect.DeepEqual(fields, ts.fields) {
+				t.Errorf("ApplyConfig(dryRun=%v) applied %v, expected %v", dryRun, fields, ts.fields)
+			}
+
+			if (err != nil) != (len(ts.fields) > 0) {
+				t.Errorf("ApplyConfig(dryRun=%v) of %d unreadable settings returned %v", dryRun, len(ts.fields), err)
+			}
+		}
+	}
+}
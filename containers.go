@@ -0,0 +1,79 @@
+package nvml
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// procRoot is where the proc filesystem is mounted
+var procRoot = "/proc"
+
+// ContainerProcess is a process running on a device, along with the container
+// and Kubernetes pod it belongs to, if any
+type ContainerProcess struct {
+	ProcessInfo
+	ContainerID string
+	PodUID      string
+}
+
+var (
+	containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+	podUIDPattern      = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+)
+
+// parseCgroup extracts the container ID and pod UID from the contents of a
+// /proc/<pid>/cgroup file. Both cgroup v1 and v2, and both the cgroupfs and
+// systemd drivers of Docker, containerd and CRI-O are understood.
+func parseCgroup(r io.Reader) (containerID, podUID string) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		path := fields[2]
+
+		if ids := containerIDPattern.FindAllString(path, -1); len(ids) > 0 && containerID == "" {
+			containerID = ids[len(ids)-1]
+		}
+		if m := podUIDPattern.FindStringSubmatch(path); m != nil && podUID == "" {
+			// The systemd driver escapes dashes in the pod UID as underscores
+			podUID = strings.ReplaceAll(m[1], "_", "-")
+		}
+	}
+
+	return containerID, podUID
+}
+
+// ComputeProcessesWithContainers returns the processes with a compute context
+// on the device, attributed to their container and pod by way of their
+// cgroups. NVML reports PIDs in the host's PID namespace, so this has to run
+// in it too. Processes that aren't containerized, or that exit before their
+// cgroup is read, are returned without a container ID.
+func (gpu *Device) ComputeProcessesWithContainers() ([]ContainerProcess, error) {
+	processes, err := gpu.ComputeRunningProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ContainerProcess
+	for _, process := range processes {
+		p := ContainerProcess{ProcessInfo: process}
+
+		f, err := os.Open(filepath.Join(procRoot, strconv.FormatUint(uint64(process.PID), 10), "cgroup"))
+		if err == nil {
+			p.ContainerID, p.PodUID = parseCgroup(f)
+			f.Close()
+		}
+
+		result = append(result, p)
+	}
+
+	return result, nil
+}
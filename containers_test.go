@@ -0,0 +1,34 @@
+package nvml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCgroup(t *testing.T) {
+	const id = "3f4a5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f6071829"
+	const uid = "0f1e2d3c-4b5a-6978-8796-a5b4c3d2e1f0"
+
+	var tests = []struct {
+		name        string
+		cgroup      string
+		containerID string
+		podUID      string
+	}{
+		{"host", "0::/user.slice/user-1000.slice/session-1.scope\n", "", ""},
+		{"docker", "12:memory:/docker/" + id + "\n1:name=systemd:/docker/" + id + "\n", id, ""},
+		{"kubepods cgroupfs", "11:cpuset:/kubepods/burstable/pod" + uid + "/" + id + "\n", id, uid},
+		{"kubepods systemd",
+			"0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod" +
+				strings.ReplaceAll(uid, "-", "_") + ".slice/cri-containerd-" + id + ".scope\n",
+			id, uid},
+		{"crio", "0::/kubepods.slice/kubepods-pod" + strings.ReplaceAll(uid, "-", "_") + ".slice/crio-" + id + ".scope\n", id, uid},
+	}
+
+	for _, ts := range tests {
+		containerID, podUID := parseCgroup(strings.NewReader(ts.cgroup))
+		if containerID != ts.containerID || podUID != ts.podUID {
+			t.Errorf("%s: parseCgroup() = %q, %q, expected %q, %q", ts.name, containerID, podUID, ts.containerID, ts.podUID)
+		}
+	}
+}
@@ -3,8 +3,14 @@ package nvml
 // See https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html
 
 /*
-#cgo CPPFLAGS: -I/usr/include/nvidia-367/ -I/usr/include/nvidia-375/ -I/usr/include/nvidia-378/ -I/usr/include/nvidia-381/ -I/usr/include/nvidia-384/
-#cgo LDFLAGS: -l nvidia-ml -L/usr/lib/nvidia-367/ -L/usr/lib/nvidia-375/ -L/usr/lib/nvidia-378/ -L/usr/lib/nvidia-381/ -L/usr/lib/nvidia-384/
+#cgo linux CPPFLAGS: -I/usr/include/nvidia-367/ -I/usr/include/nvidia-375/ -I/usr/include/nvidia-378/ -I/usr/include/nvidia-381/ -I/usr/include/nvidia-384/
+#cgo linux LDFLAGS: -l nvidia-ml -l dl -L/usr/lib/nvidia-367/ -L/usr/lib/nvidia-375/ -L/usr/lib/nvidia-378/ -L/usr/lib/nvidia-381/ -L/usr/lib/nvidia-384/
+
+// On Windows, link directly against nvml.dll. Recent drivers install it into
+// System32; older ones only ship it in the NVSMI directory, which then has to
+// be on the PATH at runtime as well.
+#cgo windows CPPFLAGS: -D_WINDOWS
+#cgo windows LDFLAGS: -L"C:/Windows/System32" -L"C:/Program Files/NVIDIA Corporation/NVSMI" -l nvml
 
 #include "nvmlbridge.h"
 */
@@ -12,187 +18,479 @@ import "C"
 
 import (
 	"errors"
-	"log"
+	"fmt"
+	"iter"
+	"sync"
 	"unsafe"
 )
 
+// Device is an NVML device. Its methods are safe for concurrent use.
 type Device struct {
+	// mu guards the handle and the cached static properties, which are
+	// replaced when the device is refreshed
+	mu         sync.RWMutex
 	nvmldevice C.nvmlDevice_t
 	index      uint
 	pcibus     string
 	name       string
 	uuid       string
+
+	// retry is the policy set with WithRetry, or nil
+	retry *RetryPolicy
+}
+
+// handle returns the device's NVML handle
+func (gpu *Device) handle() C.nvmlDevice_t {
+	gpu.mu.RLock()
+	defer gpu.mu.RUnlock()
+
+	return gpu.nvmldevice
+}
+
+// Handle returns the device's nvmlDevice_t, for use by other cgo code that
+// calls NVML directly. The handle is only valid while NVML is initialized and
+// changes when the device is refreshed.
+func (gpu *Device) Handle() uintptr {
+	cdevice := gpu.handle()
+	return *(*uintptr)(unsafe.Pointer(&cdevice))
+}
+
+// Identity returns the UUID, name and index cached when the device was
+// constructed or last refreshed, without querying NVML, so it works even
+// after the GPU is lost. Properties that weren't fetched, e.g. because of
+// WithLazyProperties, are zero.
+func (gpu *Device) Identity() (uuid, name string, index uint) {
+	gpu.mu.RLock()
+	defer gpu.mu.RUnlock()
+
+	return gpu.uuid, gpu.name, gpu.index
+}
+
+// NewDeviceFromHandle is like NewDevice, for an nvmlDevice_t obtained by other
+// cgo code and passed as a uintptr
+func NewDeviceFromHandle(handle uintptr, opts ...DeviceOption) (*Device, error) {
+	return NewDevice(*(*C.nvmlDevice_t)(unsafe.Pointer(&handle)), opts...)
+}
+
+// StaticProperty is a property NewDevice fetches once and caches in the
+// Device
+type StaticProperty int
+
+const (
+	PropUUID StaticProperty = iota
+	PropName
+	PropIndex
+	PropPciInfo
+)
+
+func (p StaticProperty) String() string {
+	switch p {
+	case PropUUID:
+		return "UUID"
+	case PropName:
+		return "Name"
+	case PropIndex:
+		return "Index"
+	case PropPciInfo:
+		return "PciInfo"
+	}
+
+	return fmt.Sprintf("StaticProperty(%d)", int(p))
+}
+
+// fetchStaticProperty fetches the given property and caches it in the device
+func (gpu *Device) fetchStaticProperty(property StaticProperty) error {
+	switch property {
+	case PropUUID:
+		uuid, err := gpu.UUID()
+		if err != nil {
+			return err
+		}
+		gpu.mu.Lock()
+		gpu.uuid = uuid
+		gpu.mu.Unlock()
+	case PropName:
+		name, err := gpu.Name()
+		if err != nil {
+			return err
+		}
+		gpu.mu.Lock()
+		gpu.name = name
+		gpu.mu.Unlock()
+	case PropIndex:
+		index, err := gpu.Index()
+		if err != nil {
+			return err
+		}
+		gpu.mu.Lock()
+		gpu.index = index
+		gpu.mu.Unlock()
+	case PropPciInfo:
+		pciinfo, err := gpu.PciInfo()
+		if err != nil {
+			return err
+		}
+		gpu.mu.Lock()
+		gpu.pcibus = pciinfo.BusID
+		gpu.mu.Unlock()
+	default:
+		return errors.New("property not found")
+	}
+
+	return nil
+}
+
+var staticProperties = []StaticProperty{PropUUID, PropName, PropIndex, PropPciInfo}
+
+type deviceOptions struct {
+	lazy     bool
+	required map[StaticProperty]bool
+	retry    *RetryPolicy
+}
+
+// DeviceOption configures how NewDevice constructs a Device
+type DeviceOption func(*deviceOptions)
+
+// WithLazyProperties makes NewDevice skip fetching the static properties, so
+// constructing a Device makes no NVML calls at all
+func WithLazyProperties() DeviceOption {
+	return func(o *deviceOptions) {
+		o.lazy = true
+	}
+}
+
+// WithRequiredProps makes NewDevice only fail if one of the given properties
+// can't be fetched. The remaining static properties are still fetched, but
+// failures are ignored, which lets callers construct devices for partially
+// broken hardware.
+func WithRequiredProps(properties ...StaticProperty) DeviceOption {
+	return func(o *deviceOptions) {
+		o.required = make(map[StaticProperty]bool)
+		for _, property := range properties {
+			o.required[property] = true
+		}
+	}
 }
 
 // NewDevice is a contstructor function for Device structs. Given an nvmlDevice_t
 // object as input, it populates some static property fields and returns a Device
-func NewDevice(cdevice C.nvmlDevice_t) (*Device, error) {
+//
+// By default all static properties are required; see WithLazyProperties and
+// WithRequiredProps.
+func NewDevice(cdevice C.nvmlDevice_t, opts ...DeviceOption) (*Device, error) {
 	device := Device{
 		nvmldevice: cdevice,
 	}
 
-	uuid, err := device.UUID()
-	if err != nil {
-		return nil, errors.New("Cannot retrieve UUID property")
+	var options deviceOptions
+	for _, opt := range opts {
+		opt(&options)
 	}
-	device.uuid = uuid
-
-	name, err := device.Name()
-	if err != nil {
-		return nil, errors.New("Cannot retrieve Name property")
+	device.retry = options.retry
+	if options.lazy {
+		return &device, nil
 	}
-	device.name = name
 
-	index, err := device.Index()
-	if err != nil {
-		return nil, errors.New("Cannot retrieve Index property")
+	for _, property := range staticProperties {
+		err := device.fetchStaticProperty(property)
+		if err != nil && (options.required == nil || options.required[property]) {
+			return nil, fmt.Errorf("Cannot retrieve %s property: %w", property, err)
+		}
 	}
-	device.index = index
 
 	return &device, nil
 }
 
-func (gpu *Device) PowerState() (int, error) {
-	var pstate C.nvmlPstates_t
+// Refresh re-resolves the device's handle by its UUID and re-fetches its
+// static properties, so a Device outlives a driver reload or a GPU being
+// re-enumerated at a different index. The device must have been constructed
+// with its UUID.
+func (gpu *Device) Refresh() error {
 	var result C.nvmlReturn_t
+	var cdevice C.nvmlDevice_t
 
-	result = C.nvmlDeviceGetPowerState(gpu.nvmldevice, &pstate)
-	if result != C.NVML_SUCCESS {
-		return -1, errors.New("GetPowerState returned error")
+	if err := requireFunction("nvmlDeviceGetHandleByUUID"); err != nil {
+		return err
 	}
 
-	return int(pstate), nil
-}
+	gpu.mu.RLock()
+	uuid := gpu.uuid
+	gpu.mu.RUnlock()
+	if uuid == "" {
+		return errors.New("device has no cached UUID to refresh by")
+	}
 
-// Temp returns the current temperature of the card in degrees Celsius
-func (gpu *Device) Temp() (uint, error) {
-	var result C.nvmlReturn_t
-	var ctemp C.uint
+	cuuid := C.CString(uuid)
+	defer C.free(unsafe.Pointer(cuuid))
 
-	result = C.nvmlDeviceGetTemperature(gpu.nvmldevice, C.NVML_TEMPERATURE_GPU, &ctemp)
+	result = C.nvmlDeviceGetHandleByUUID(cuuid, &cdevice)
 	if result != C.NVML_SUCCESS {
-		return 0, errors.New("GetPowerState returned error")
+		return newNVMLError("nvmlDeviceGetHandleByUUID", result)
 	}
 
-	return uint(ctemp), nil
+	gpu.mu.Lock()
+	gpu.nvmldevice = cdevice
+	gpu.mu.Unlock()
+
+	for _, property := range staticProperties {
+		if err := gpu.fetchStaticProperty(property); err != nil {
+			return fmt.Errorf("Cannot retrieve %s property: %w", property, err)
+		}
+	}
+
+	return nil
 }
 
-type cIntPropFunc struct {
-	f C.getintProperty
-}
-
-var intpropfunctions = map[string]*cIntPropFunc{
-	"Index":                        {C.getintProperty(C.nvmlDeviceGetIndex)},
-	"MinorNumber":                  {C.getintProperty(C.nvmlDeviceGetMinorNumber)},
-	"InforomConfigurationChecksum": {C.getintProperty(C.nvmlDeviceGetInforomConfigurationChecksum)},
-	"MaxPCIeLinkGeneration":        {C.getintProperty(C.nvmlDeviceGetMaxPcieLinkGeneration)},
-	"MaxPCIeLinkWidth":             {C.getintProperty(C.nvmlDeviceGetMaxPcieLinkWidth)},
-	"CurrPCIeLinkGeneration":       {C.getintProperty(C.nvmlDeviceGetCurrPcieLinkGeneration)},
-	"CurrPCIeLinkWidth":            {C.getintProperty(C.nvmlDeviceGetCurrPcieLinkWidth)},
-	"PCIeReplayCounter":            {C.getintProperty(C.nvmlDeviceGetPcieReplayCounter)},
-	"FanSpeed":                     {C.getintProperty(C.nvmlDeviceGetFanSpeed)},
-	"PowerManagementLimit":         {C.getintProperty(C.nvmlDeviceGetPowerManagementLimit)},
-	"PowerManagementDefaultLimit":  {C.getintProperty(C.nvmlDeviceGetPowerManagementDefaultLimit)},
-	"PowerUsage":                   {C.getintProperty(C.nvmlDeviceGetPowerUsage)},
-	"EnforcedPowerLimit":           {C.getintProperty(C.nvmlDeviceGetEnforcedPowerLimit)},
-	"BoardId":                      {C.getintProperty(C.nvmlDeviceGetBoardId)},
-	"MultiGpuBoard":                {C.getintProperty(C.nvmlDeviceGetMultiGpuBoard)},
+// TopologyChange is the result of RefreshTopology
+type TopologyChange struct {
+	// Devices are the devices currently present, in NVML index order
+	Devices []*Device
+	// Added are the devices that weren't known before
+	Added []*Device
+	// Removed are the known devices that are no longer present
+	Removed []*Device
 }
 
-func (gpu *Device) intProperty(property string) (uint, error) {
-	var cuintproperty C.uint
+// topologyKeys returns the UUID and PCI bus ID of gpu, queried rather than
+// cached so that devices with lazy properties can be told apart. Either is
+// empty if it can't be read.
+func topologyKeys(gpu *Device) (uuid, busID string) {
+	if id, err := gpu.UUID(); err == nil {
+		uuid = id
+	}
+	if pciinfo, err := gpu.PciInfo(); err == nil {
+		if id, err := pciinfo.PCIBusID(); err == nil {
+			busID = id.String()
+		}
+	}
 
-	ipf, ok := intpropfunctions[property]
-	if ok == false {
-		return 0, errors.New("property not found")
+	return uuid, busID
+}
+
+// RefreshTopology reconciles a previously enumerated list of devices with the
+// devices currently present. Devices are matched by UUID, or by PCI bus ID if
+// the UUID can't be read. Known devices that are still present are refreshed
+// in place, so pointers to them stay valid; known devices that can't be
+// identified any more are reported as removed. Errors for individual devices
+// are joined, as in GetAllGPUs.
+func RefreshTopology(known []*Device) (TopologyChange, error) {
+	var change TopologyChange
+	var errs []error
+
+	byUUID := make(map[string]*Device)
+	byBusID := make(map[string]*Device)
+	for _, gpu := range known {
+		// Devices that are gone show up as Removed below
+		err := gpu.Refresh()
+		var nvmlerr *NVMLError
+		if err != nil && !(errors.As(err, &nvmlerr) && nvmlerr.Code == C.NVML_ERROR_NOT_FOUND) {
+			errs = append(errs, err)
+		}
+
+		uuid, busID := topologyKeys(gpu)
+		if uuid != "" {
+			byUUID[uuid] = gpu
+		}
+		if busID != "" {
+			byBusID[busID] = gpu
+		}
 	}
 
-	result := C.bridge_get_int_property(ipf.f, gpu.nvmldevice, &cuintproperty)
-	if result != C.EXIT_SUCCESS {
-		return 0, errors.New("getintProperty bridge returned error")
+	current, err := GetAllGPUs()
+	if err != nil {
+		errs = append(errs, err)
 	}
 
-	return uint(cuintproperty), nil
-}
+	matched := make(map[*Device]bool)
+	for _, gpu := range current {
+		uuid, busID := topologyKeys(gpu)
 
-// Index returns the NVML index of the device.
-func (gpu *Device) Index() (uint, error) {
-	return gpu.intProperty("Index")
-}
+		existing := byUUID[uuid]
+		if existing == nil {
+			existing = byBusID[busID]
+		}
+		if existing != nil && !matched[existing] {
+			matched[existing] = true
+			change.Devices = append(change.Devices, existing)
+			continue
+		}
 
-// MinorNumber returns the minor number of the device. The minor number
-// is the integer such that the device node file for the GPU will be
-// /dev/nvidia[Device.MinorNumber]
-func (gpu *Device) MinorNumber() (uint, error) {
-	return gpu.intProperty("MinorNumber")
-}
+		change.Devices = append(change.Devices, gpu)
+		change.Added = append(change.Added, gpu)
+	}
 
-// InforomConfigurationChecksum returns the checksum of the configuration
-// stored in the device's inforom. (Can be used to verify identical configuration
-// between devices.)
-func (gpu *Device) InforomConfigurationChecksum() (uint, error) {
-	return gpu.intProperty("InforomConfigurationChecksum")
-}
+	for _, gpu := range known {
+		if !matched[gpu] {
+			change.Removed = append(change.Removed, gpu)
+		}
+	}
 
-// MaxPCIeLinkGeneration returns the maximum PCIe link generation possible with this
-// device and system.
-func (gpu *Device) MaxPCIeLinkGeneration() (uint, error) {
-	return gpu.intProperty("MaxPCIeLinkGeneration")
+	return change, errors.Join(errs...)
 }
 
-// MaxPCIeLinkWidth returns the maximum PCIe link width possible with this device
-// and system
-func (gpu *Device) MaxPCIeLinkWidth() (uint, error) {
-	return gpu.intProperty("MaxPCIeLinkWidth")
+// Pstate is a performance state of the device, from P0 (maximum performance)
+// to P15 (minimum performance).
+type Pstate int
+
+const (
+	P0            Pstate = C.NVML_PSTATE_0
+	P1            Pstate = C.NVML_PSTATE_1
+	P2            Pstate = C.NVML_PSTATE_2
+	P3            Pstate = C.NVML_PSTATE_3
+	P4            Pstate = C.NVML_PSTATE_4
+	P5            Pstate = C.NVML_PSTATE_5
+	P6            Pstate = C.NVML_PSTATE_6
+	P7            Pstate = C.NVML_PSTATE_7
+	P8            Pstate = C.NVML_PSTATE_8
+	P9            Pstate = C.NVML_PSTATE_9
+	P10           Pstate = C.NVML_PSTATE_10
+	P11           Pstate = C.NVML_PSTATE_11
+	P12           Pstate = C.NVML_PSTATE_12
+	P13           Pstate = C.NVML_PSTATE_13
+	P14           Pstate = C.NVML_PSTATE_14
+	P15           Pstate = C.NVML_PSTATE_15
+	PstateUnknown Pstate = C.NVML_PSTATE_UNKNOWN
+)
+
+func (p Pstate) String() string {
+	switch {
+	case p >= P0 && p <= P15:
+		return fmt.Sprintf("P%d", int(p))
+	case p == PstateUnknown:
+		return "Unknown"
+	}
+
+	return fmt.Sprintf("Pstate(%d)", int(p))
 }
 
-// CurrPCIeLinkGeneration returns the current PCIe link generation number
-func (gpu *Device) CurrPCIeLinkGeneration() (uint, error) {
-	return gpu.intProperty("CurrPCIeLinkGeneration")
+// PerformanceState returns the current performance state of the device.
+func (gpu *Device) PerformanceState() (Pstate, error) {
+	var pstate C.nvmlPstates_t
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceGetPerformanceState"); err != nil {
+		return PstateUnknown, err
+	}
+
+	result = C.nvmlDeviceGetPerformanceState(gpu.handle(), &pstate)
+	if result != C.NVML_SUCCESS {
+		return PstateUnknown, newNVMLError("nvmlDeviceGetPerformanceState", result)
+	}
+
+	return Pstate(pstate), nil
 }
 
-// CurrPCIeLinkWidth returns the current PCIe link width
-func (gpu *Device) CurrPCIeLinkWidth() (uint, error) {
-	return gpu.intProperty("CurrPCIeLinkWidth")
+// PowerState returns the current performance state of the device as an int.
+//
+// Deprecated: Use PerformanceState instead.
+func (gpu *Device) PowerState() (int, error) {
+	pstate, err := gpu.PerformanceState()
+	if err != nil {
+		return -1, err
+	}
+
+	return int(pstate), nil
 }
 
-// PCIeReplayCounter returns the replay counter and rollover info.
-func (gpu *Device) PCIeReplayCounter() (uint, error) {
-	return gpu.intProperty("PCIeReplayCounter")
+// TemperatureSensor is a temperature sensor on the device. The vendored NVML
+// headers only define the GPU die sensor; the memory (HBM) temperature is not
+// exposed through nvmlDeviceGetTemperature.
+type TemperatureSensor int
+
+const (
+	TemperatureGPU TemperatureSensor = C.NVML_TEMPERATURE_GPU
+)
+
+func (s TemperatureSensor) String() string {
+	switch s {
+	case TemperatureGPU:
+		return "GPU"
+	}
+
+	return fmt.Sprintf("TemperatureSensor(%d)", int(s))
 }
 
-// FanSpeed returns the current fan speed of the device, on devices that
-// have fans.
-func (gpu *Device) FanSpeed() (uint, error) {
-	return gpu.intProperty("FanSpeed")
+// Temperature returns the current temperature reading of the given sensor, in
+// degrees Celsius
+func (gpu *Device) Temperature(sensor TemperatureSensor) (uint, error) {
+	var result C.nvmlReturn_t
+	var ctemp C.uint
+
+	if err := requireFunction("nvmlDeviceGetTemperature"); err != nil {
+		return 0, err
+	}
+
+	result = C.nvmlDeviceGetTemperature(gpu.handle(), C.nvmlTemperatureSensors_t(sensor), &ctemp)
+	if result != C.NVML_SUCCESS {
+		return 0, newNVMLError("nvmlDeviceGetTemperature", result)
+	}
+
+	return uint(ctemp), nil
 }
 
-// PowerManagementLimit returns the power management limit for the device, in mW
-func (gpu *Device) PowerManagementLimit() (uint, error) {
-	return gpu.intProperty("PowerManagementLimit")
+// Temp returns the current temperature of the card in degrees Celsius
+func (gpu *Device) Temp() (uint, error) {
+	return gpu.Temperature(TemperatureGPU)
 }
 
-// PowerManagementDefaultLimit returns the upper limit for the amount of power
-// the card is allowed to draw, in mW.
-func (gpu *Device) PowerManagementDefaultLimit() (uint, error) {
-	return gpu.intProperty("PowerManagementDefaultLimit")
+//go:generate go run ./internal/nvmlgen
+
+// cIntPropFunc is an entry of intpropfunctions, which nvmlgen generates from
+// nvml.h with an entry for each device getter that returns an unsigned int.
+// Keys are the NVML function names without the nvmlDeviceGet prefix. The
+// function is looked up by symbol on first use, so that drivers lacking it
+// still load.
+type cIntPropFunc struct {
+	symbol string
+	once   sync.Once
+	f      C.getintProperty
 }
 
-// PowerUsage returns the current power usage of the device, in mW.
-func (gpu *Device) PowerUsage() (uint, error) {
-	return gpu.intProperty("PowerUsage")
+func (ipf *cIntPropFunc) function() C.getintProperty {
+	ipf.once.Do(func() {
+		ipf.f = C.getintProperty(lookupFunction(ipf.symbol))
+	})
+	return ipf.f
 }
 
-// EnforcedPowerLimit returns the effective power limit that the driver enforces after
-// taking into account all limiters.
-func (gpu *Device) EnforcedPowerLimit() (uint, error) {
-	return gpu.intProperty("EnforcedPowerLimit")
+func (gpu *Device) intProperty(property string) (uint, error) {
+	var cuintproperty C.uint
+
+	ipf, ok := intpropfunctions[property]
+	if ok == false {
+		return 0, errors.New("property not found")
+	}
+
+	f := ipf.function()
+	if f == nil {
+		return 0, functionNotFound("nvmlDeviceGet" + property)
+	}
+
+	return withRetry(gpu, func() (uint, error) {
+		result := C.bridge_get_int_property(f, gpu.handle(), &cuintproperty)
+		if result != C.NVML_SUCCESS {
+			return 0, newNVMLError("nvmlDeviceGet"+property, C.nvmlReturn_t(result))
+		}
+
+		return uint(cuintproperty), nil
+	})
 }
 
-// BoardID returns the device boardId, which will be identical for GPUs connected to
-// the same PLX
-func (gpu *Device) BoardId() (uint, error) {
-	return gpu.intProperty("BoardId")
+// SetPowerManagementLimit sets the power management limit for the device, in mW.
+// The limit must be within the device's power limit constraints. Requires root.
+func (gpu *Device) SetPowerManagementLimit(milliwatts uint) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetPowerManagementLimit"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceSetPowerManagementLimit(gpu.handle(), C.uint(milliwatts))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetPowerManagementLimit", result)
+	}
+
+	return nil
 }
 
 // GetDecoderUtilization retrieves the current utilization and sampling size in
@@ -202,9 +500,13 @@ func (gpu *Device) GetDecoderUtilization() (utilization uint, samplingPeriosUs u
 	var ctemp C.uint
 	var ctemp2 C.uint
 
-	result = C.nvmlDeviceGetDecoderUtilization(gpu.nvmldevice, &ctemp, &ctemp2)
+	if err := requireFunction("nvmlDeviceGetDecoderUtilization"); err != nil {
+		return 0, 0, err
+	}
+
+	result = C.nvmlDeviceGetDecoderUtilization(gpu.handle(), &ctemp, &ctemp2)
 	if result != C.NVML_SUCCESS {
-		return 0, 0, errors.New("GetDecoderUtilization returned error")
+		return 0, 0, newNVMLError("nvmlDeviceGetDecoderUtilization", result)
 	}
 
 	return uint(ctemp), uint(ctemp2), nil
@@ -217,9 +519,13 @@ func (gpu *Device) GetEncoderUtilization() (utilization uint, samplingPeriosUs u
 	var ctemp C.uint
 	var ctemp2 C.uint
 
-	result = C.nvmlDeviceGetEncoderUtilization(gpu.nvmldevice, &ctemp, &ctemp2)
+	if err := requireFunction("nvmlDeviceGetEncoderUtilization"); err != nil {
+		return 0, 0, err
+	}
+
+	result = C.nvmlDeviceGetEncoderUtilization(gpu.handle(), &ctemp, &ctemp2)
 	if result != C.NVML_SUCCESS {
-		return 0, 0, errors.New("GetEncoderUtilization returned error")
+		return 0, 0, newNVMLError("nvmlDeviceGetEncoderUtilization", result)
 	}
 
 	return uint(ctemp), uint(ctemp2), nil
@@ -230,35 +536,62 @@ func (gpu *Device) GetUtilizationRates() (gpuUtilization uint, memoryUtilization
 	var result C.nvmlReturn_t
 	var ctemp C.nvmlUtilization_t
 
-	result = C.nvmlDeviceGetUtilizationRates(gpu.nvmldevice, &ctemp)
+	if err := requireFunction("nvmlDeviceGetUtilizationRates"); err != nil {
+		return 0, 0, err
+	}
+
+	result = C.nvmlDeviceGetUtilizationRates(gpu.handle(), &ctemp)
 	if result != C.NVML_SUCCESS {
-		return 0, 0, errors.New("GetUtilizationRates returned error")
+		return 0, 0, newNVMLError("nvmlDeviceGetUtilizationRates", result)
 	}
 
 	return uint(ctemp.gpu), uint(ctemp.memory), nil
 }
 
-// MultiGpuBoard
+// MultiGpuBoard reports whether the device is on a multi-GPU board, such as
+// the Tesla K80
 func (gpu *Device) MultiGpuBoard() (bool, error) {
 	p, err := gpu.intProperty("MultiGpuBoard")
-	if int(p) == 0 {
-		return true, err
-	} else {
+	if err != nil {
 		return false, err
 	}
+
+	return p != 0, nil
+}
+
+// OnSameBoard reports whether the device and other are on the same physical
+// board
+func (gpu *Device) OnSameBoard(other *Device) (bool, error) {
+	var result C.nvmlReturn_t
+	var consameboard C.int
+
+	if err := requireFunction("nvmlDeviceOnSameBoard"); err != nil {
+		return false, err
+	}
+
+	result = C.nvmlDeviceOnSameBoard(gpu.handle(), other.handle(), &consameboard)
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceOnSameBoard", result)
+	}
+
+	return consameboard != 0, nil
 }
 
+// cTextPropFunc is an entry of textpropfunctions, which nvmlgen generates from
+// nvml.h with an entry for each device getter that fills a string buffer. Like
+// cIntPropFunc, the function is looked up by symbol on first use.
 type cTextPropFunc struct {
-	f      C.gettextProperty
+	symbol string
 	length C.uint
+	once   sync.Once
+	f      C.gettextProperty
 }
 
-var textpropfunctions = map[string]*cTextPropFunc{
-	"Name":                {C.gettextProperty(C.nvmlDeviceGetName), C.NVML_DEVICE_NAME_BUFFER_SIZE},
-	"Serial":              {C.gettextProperty(C.nvmlDeviceGetSerial), C.NVML_DEVICE_SERIAL_BUFFER_SIZE},
-	"UUID":                {C.gettextProperty(C.nvmlDeviceGetUUID), C.NVML_DEVICE_UUID_BUFFER_SIZE},
-	"InforomImageVersion": {C.gettextProperty(C.nvmlDeviceGetInforomImageVersion), C.NVML_DEVICE_INFOROM_VERSION_BUFFER_SIZE},
-	"VbiosVersion":        {C.gettextProperty(C.nvmlDeviceGetVbiosVersion), C.NVML_DEVICE_VBIOS_VERSION_BUFFER_SIZE},
+func (tpf *cTextPropFunc) function() C.gettextProperty {
+	tpf.once.Do(func() {
+		tpf.f = C.gettextProperty(lookupFunction(tpf.symbol))
+	})
+	return tpf.f
 }
 
 // textProperty takes a propertyname as input and then runs the corresponding
@@ -276,12 +609,20 @@ func (gpu *Device) textProperty(property string) (string, error) {
 		return "", errors.New("property not found")
 	}
 
+	f := tpf.function()
+	if f == nil {
+		return "", functionNotFound("nvmlDeviceGet" + property)
+	}
+
 	var buf *C.char = genCStringBuffer(uint(tpf.length))
 	defer C.free(unsafe.Pointer(buf))
 
-	result := C.bridge_get_text_property(tpf.f, gpu.nvmldevice, buf, tpf.length)
-	if result != C.EXIT_SUCCESS {
-		return propvalue, errors.New("gettextProperty bridge returned error")
+	_, err := withRetry(gpu, func() (struct{}, error) {
+		result := C.bridge_get_text_property(f, gpu.handle(), buf, tpf.length)
+		return struct{}{}, newNVMLError("nvmlDeviceGet"+property, C.nvmlReturn_t(result))
+	})
+	if err != nil {
+		return propvalue, err
 	}
 
 	propvalue = strndup(buf, uint(tpf.length))
@@ -292,29 +633,697 @@ func (gpu *Device) textProperty(property string) (string, error) {
 	}
 }
 
-// InforomImageVersion returns the global inforom image version
-func (gpu *Device) InforomImageVersion() (string, error) {
-	return gpu.textProperty("InforomImageVersion")
+// InforomObject is an object stored in the device's inforom
+type InforomObject int
+
+const (
+	InforomOEM   InforomObject = C.NVML_INFOROM_OEM
+	InforomECC   InforomObject = C.NVML_INFOROM_ECC
+	InforomPower InforomObject = C.NVML_INFOROM_POWER
+)
+
+func (o InforomObject) String() string {
+	switch o {
+	case InforomOEM:
+		return "OEM"
+	case InforomECC:
+		return "ECC"
+	case InforomPower:
+		return "Power"
+	}
+
+	return fmt.Sprintf("InforomObject(%d)", int(o))
+}
+
+// InforomVersion returns the version of the given object in the device's
+// inforom
+func (gpu *Device) InforomVersion(object InforomObject) (string, error) {
+	if err := requireFunction("nvmlDeviceGetInforomVersion"); err != nil {
+		return "", err
+	}
+
+	var buf *C.char = genCStringBuffer(C.NVML_DEVICE_INFOROM_VERSION_BUFFER_SIZE)
+	defer C.free(unsafe.Pointer(buf))
+
+	result := C.nvmlDeviceGetInforomVersion(gpu.handle(), C.nvmlInforomObject_t(object), buf, C.NVML_DEVICE_INFOROM_VERSION_BUFFER_SIZE)
+	if result != C.NVML_SUCCESS {
+		return "", newNVMLError("nvmlDeviceGetInforomVersion", result)
+	}
+
+	return strndup(buf, C.NVML_DEVICE_INFOROM_VERSION_BUFFER_SIZE), nil
+}
+
+// ValidateInforom checks that the device's inforom isn't corrupted. It returns
+// an error if the checksum of the inforom is invalid or it couldn't be read;
+// a corrupted inforom is reported as an *NVMLError with Code
+// ReturnCorruptedInforom.
+func (gpu *Device) ValidateInforom() error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceValidateInforom"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceValidateInforom(gpu.handle())
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceValidateInforom", result)
+	}
+
+	return nil
 }
 
-// VbiosVersion returns the VBIOS version of the device
-func (gpu *Device) VbiosVersion() (string, error) {
-	return gpu.textProperty("VbiosVersion")
+// Brand is the brand (product line) of a device
+type Brand int
+
+const (
+	BrandUnknown Brand = C.NVML_BRAND_UNKNOWN
+	BrandQuadro  Brand = C.NVML_BRAND_QUADRO
+	BrandTesla   Brand = C.NVML_BRAND_TESLA
+	BrandNVS     Brand = C.NVML_BRAND_NVS
+	BrandGRID    Brand = C.NVML_BRAND_GRID
+	BrandGeForce Brand = C.NVML_BRAND_GEFORCE
+)
+
+func (b Brand) String() string {
+	switch b {
+	case BrandQuadro:
+		return "Quadro"
+	case BrandTesla:
+		return "Tesla"
+	case BrandNVS:
+		return "NVS"
+	case BrandGRID:
+		return "GRID"
+	case BrandGeForce:
+		return "GeForce"
+	case BrandUnknown:
+		return "Unknown"
+	}
+
+	return fmt.Sprintf("Brand(%d)", int(b))
 }
 
-// Return the product name of the device, e.g. "Tesla K40m"
-func (gpu *Device) Name() (string, error) {
-	return gpu.textProperty("Name")
+// Brand returns the brand of the device
+func (gpu *Device) Brand() (Brand, error) {
+	var result C.nvmlReturn_t
+	var cbrand C.nvmlBrandType_t
+
+	if err := requireFunction("nvmlDeviceGetBrand"); err != nil {
+		return BrandUnknown, err
+	}
+
+	result = C.nvmlDeviceGetBrand(gpu.handle(), &cbrand)
+	if result != C.NVML_SUCCESS {
+		return BrandUnknown, newNVMLError("nvmlDeviceGetBrand", result)
+	}
+
+	return Brand(cbrand), nil
 }
 
-// Return the UUID of the device
-func (gpu *Device) UUID() (string, error) {
-	return gpu.textProperty("UUID")
+// ClockType is a clock domain of the device
+type ClockType int
+
+const (
+	ClockGraphics ClockType = C.NVML_CLOCK_GRAPHICS
+	ClockSM       ClockType = C.NVML_CLOCK_SM
+	ClockMem      ClockType = C.NVML_CLOCK_MEM
+	ClockVideo    ClockType = C.NVML_CLOCK_VIDEO
+)
+
+func (t ClockType) String() string {
+	switch t {
+	case ClockGraphics:
+		return "Graphics"
+	case ClockSM:
+		return "SM"
+	case ClockMem:
+		return "Mem"
+	case ClockVideo:
+		return "Video"
+	}
+
+	return fmt.Sprintf("ClockType(%d)", int(t))
 }
 
-// Return the serial number of the device
-func (gpu *Device) Serial() (string, error) {
-	return gpu.textProperty("Serial")
+// ApplicationsClock returns the clock, in MHz, that compute and graphics
+// applications will be boosted to for the given clock domain.
+func (gpu *Device) ApplicationsClock(clockType ClockType) (uint, error) {
+	var result C.nvmlReturn_t
+	var cclock C.uint
+
+	if err := requireFunction("nvmlDeviceGetApplicationsClock"); err != nil {
+		return 0, err
+	}
+
+	result = C.nvmlDeviceGetApplicationsClock(gpu.handle(), C.nvmlClockType_t(clockType), &cclock)
+	if result != C.NVML_SUCCESS {
+		return 0, newNVMLError("nvmlDeviceGetApplicationsClock", result)
+	}
+
+	return uint(cclock), nil
+}
+
+// SetApplicationsClocks sets the memory and graphics clocks, in MHz, that
+// compute and graphics applications will be boosted to. Requires root.
+func (gpu *Device) SetApplicationsClocks(memClockMHz, graphicsClockMHz uint) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetApplicationsClocks"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceSetApplicationsClocks(gpu.handle(), C.uint(memClockMHz), C.uint(graphicsClockMHz))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetApplicationsClocks", result)
+	}
+
+	return nil
+}
+
+// ClocksThrottleReason is a set of reasons the device's clocks are being held
+// below their maximum, combined with bitwise or
+type ClocksThrottleReason uint64
+
+const (
+	ClocksThrottleReasonGpuIdle                   ClocksThrottleReason = C.nvmlClocksThrottleReasonGpuIdle
+	ClocksThrottleReasonApplicationsClocksSetting ClocksThrottleReason = C.nvmlClocksThrottleReasonApplicationsClocksSetting
+	ClocksThrottleReasonSwPowerCap                ClocksThrottleReason = C.nvmlClocksThrottleReasonSwPowerCap
+	ClocksThrottleReasonHwSlowdown                ClocksThrottleReason = C.nvmlClocksThrottleReasonHwSlowdown
+	ClocksThrottleReasonSyncBoost                 ClocksThrottleReason = C.nvmlClocksThrottleReasonSyncBoost
+	ClocksThrottleReasonUnknown                   ClocksThrottleReason = C.nvmlClocksThrottleReasonUnknown
+	ClocksThrottleReasonNone                      ClocksThrottleReason = C.nvmlClocksThrottleReasonNone
+)
+
+// ClocksThrottleReasons lists the individual reasons, for iterating over a set
+var ClocksThrottleReasons = []ClocksThrottleReason{
+	ClocksThrottleReasonGpuIdle,
+	ClocksThrottleReasonApplicationsClocksSetting,
+	ClocksThrottleReasonSwPowerCap,
+	ClocksThrottleReasonHwSlowdown,
+	ClocksThrottleReasonSyncBoost,
+	ClocksThrottleReasonUnknown,
+}
+
+func (r ClocksThrottleReason) String() string {
+	switch r {
+	case ClocksThrottleReasonGpuIdle:
+		return "GpuIdle"
+	case ClocksThrottleReasonApplicationsClocksSetting:
+		return "ApplicationsClocksSetting"
+	case ClocksThrottleReasonSwPowerCap:
+		return "SwPowerCap"
+	case ClocksThrottleReasonHwSlowdown:
+		return "HwSlowdown"
+	case ClocksThrottleReasonSyncBoost:
+		return "SyncBoost"
+	case ClocksThrottleReasonUnknown:
+		return "Unknown"
+	case ClocksThrottleReasonNone:
+		return "None"
+	}
+
+	return maskString(r, ClocksThrottleReasons)
+}
+
+// CurrentClocksThrottleReasons returns the reasons the clocks are currently
+// being throttled
+func (gpu *Device) CurrentClocksThrottleReasons() (ClocksThrottleReason, error) {
+	var result C.nvmlReturn_t
+	var creasons C.ulonglong
+
+	if err := requireFunction("nvmlDeviceGetCurrentClocksThrottleReasons"); err != nil {
+		return ClocksThrottleReasonNone, err
+	}
+
+	result = C.nvmlDeviceGetCurrentClocksThrottleReasons(gpu.handle(), &creasons)
+	if result != C.NVML_SUCCESS {
+		return ClocksThrottleReasonNone, newNVMLError("nvmlDeviceGetCurrentClocksThrottleReasons", result)
+	}
+
+	return ClocksThrottleReason(creasons), nil
+}
+
+// ComputeMode determines whether and how many compute contexts may run on the
+// device at once
+type ComputeMode int
+
+const (
+	ComputeModeDefault          ComputeMode = C.NVML_COMPUTEMODE_DEFAULT
+	ComputeModeExclusiveThread  ComputeMode = C.NVML_COMPUTEMODE_EXCLUSIVE_THREAD
+	ComputeModeProhibited       ComputeMode = C.NVML_COMPUTEMODE_PROHIBITED
+	ComputeModeExclusiveProcess ComputeMode = C.NVML_COMPUTEMODE_EXCLUSIVE_PROCESS
+)
+
+func (m ComputeMode) String() string {
+	switch m {
+	case ComputeModeDefault:
+		return "Default"
+	case ComputeModeExclusiveThread:
+		return "ExclusiveThread"
+	case ComputeModeProhibited:
+		return "Prohibited"
+	case ComputeModeExclusiveProcess:
+		return "ExclusiveProcess"
+	}
+
+	return fmt.Sprintf("ComputeMode(%d)", int(m))
+}
+
+// ComputeMode returns the current compute mode of the device
+func (gpu *Device) ComputeMode() (ComputeMode, error) {
+	var result C.nvmlReturn_t
+	var cmode C.nvmlComputeMode_t
+
+	if err := requireFunction("nvmlDeviceGetComputeMode"); err != nil {
+		return 0, err
+	}
+
+	result = C.nvmlDeviceGetComputeMode(gpu.handle(), &cmode)
+	if result != C.NVML_SUCCESS {
+		return 0, newNVMLError("nvmlDeviceGetComputeMode", result)
+	}
+
+	return ComputeMode(cmode), nil
+}
+
+// SetComputeMode sets the compute mode of the device. Requires root.
+func (gpu *Device) SetComputeMode(mode ComputeMode) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetComputeMode"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceSetComputeMode(gpu.handle(), C.nvmlComputeMode_t(mode))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetComputeMode", result)
+	}
+
+	return nil
+}
+
+// PersistenceMode returns whether persistence mode is enabled, i.e. whether
+// the driver stays loaded when no clients are connected. Linux only.
+func (gpu *Device) PersistenceMode() (bool, error) {
+	var result C.nvmlReturn_t
+	var cmode C.nvmlEnableState_t
+
+	if err := requireFunction("nvmlDeviceGetPersistenceMode"); err != nil {
+		return false, err
+	}
+
+	result = C.nvmlDeviceGetPersistenceMode(gpu.handle(), &cmode)
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceGetPersistenceMode", result)
+	}
+
+	return cmode == C.NVML_FEATURE_ENABLED, nil
+}
+
+// SetPersistenceMode enables or disables persistence mode. Linux only,
+// requires root.
+func (gpu *Device) SetPersistenceMode(enabled bool) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetPersistenceMode"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceSetPersistenceMode(gpu.handle(), enableState(enabled))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetPersistenceMode", result)
+	}
+
+	return nil
+}
+
+// AutoBoostedClocksEnabled returns whether the device may boost its clocks
+// automatically, and whether it does so by default
+func (gpu *Device) AutoBoostedClocksEnabled() (enabled, defaultEnabled bool, err error) {
+	var result C.nvmlReturn_t
+	var cenabled, cdefault C.nvmlEnableState_t
+
+	if err := requireFunction("nvmlDeviceGetAutoBoostedClocksEnabled"); err != nil {
+		return false, false, err
+	}
+
+	result = C.nvmlDeviceGetAutoBoostedClocksEnabled(gpu.handle(), &cenabled, &cdefault)
+	if result != C.NVML_SUCCESS {
+		return false, false, newNVMLError("nvmlDeviceGetAutoBoostedClocksEnabled", result)
+	}
+
+	return cenabled == C.NVML_FEATURE_ENABLED, cdefault == C.NVML_FEATURE_ENABLED, nil
+}
+
+// SetAutoBoostedClocksEnabled allows or forbids automatic clock boosting.
+// Requires root unless the SetAutoBoostedClocks API restriction is lifted.
+func (gpu *Device) SetAutoBoostedClocksEnabled(enabled bool) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetAutoBoostedClocksEnabled"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceSetAutoBoostedClocksEnabled(gpu.handle(), enableState(enabled))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetAutoBoostedClocksEnabled", result)
+	}
+
+	return nil
+}
+
+// AccountingMode returns whether per-process accounting is enabled
+func (gpu *Device) AccountingMode() (bool, error) {
+	var result C.nvmlReturn_t
+	var cmode C.nvmlEnableState_t
+
+	if err := requireFunction("nvmlDeviceGetAccountingMode"); err != nil {
+		return false, err
+	}
+
+	result = C.nvmlDeviceGetAccountingMode(gpu.handle(), &cmode)
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceGetAccountingMode", result)
+	}
+
+	return cmode == C.NVML_FEATURE_ENABLED, nil
+}
+
+// SetAccountingMode enables or disables per-process accounting. Requires
+// root.
+func (gpu *Device) SetAccountingMode(enabled bool) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetAccountingMode"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceSetAccountingMode(gpu.handle(), enableState(enabled))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetAccountingMode", result)
+	}
+
+	return nil
+}
+
+// GpuOperationMode is the GPU Operation Mode (GOM) of a device, which allows
+// parts of the GPU to be disabled to save power or increase performance.
+// Supported on GK110 M-class and X-class Tesla products and some GeForce
+// Titan products.
+type GpuOperationMode int
+
+const (
+	GomAllOn   GpuOperationMode = C.NVML_GOM_ALL_ON
+	GomCompute GpuOperationMode = C.NVML_GOM_COMPUTE
+	GomLowDP   GpuOperationMode = C.NVML_GOM_LOW_DP
+)
+
+func (m GpuOperationMode) String() string {
+	switch m {
+	case GomAllOn:
+		return "AllOn"
+	case GomCompute:
+		return "Compute"
+	case GomLowDP:
+		return "LowDP"
+	}
+
+	return fmt.Sprintf("GpuOperationMode(%d)", int(m))
+}
+
+// GpuOperationMode returns the current GOM and the pending GOM, i.e. the one
+// the device will switch to after the next reboot.
+func (gpu *Device) GpuOperationMode() (current, pending GpuOperationMode, err error) {
+	var result C.nvmlReturn_t
+	var ccurrent, cpending C.nvmlGpuOperationMode_t
+
+	if err := requireFunction("nvmlDeviceGetGpuOperationMode"); err != nil {
+		return 0, 0, err
+	}
+
+	result = C.nvmlDeviceGetGpuOperationMode(gpu.handle(), &ccurrent, &cpending)
+	if result != C.NVML_SUCCESS {
+		return 0, 0, newNVMLError("nvmlDeviceGetGpuOperationMode", result)
+	}
+
+	return GpuOperationMode(ccurrent), GpuOperationMode(cpending), nil
+}
+
+// SetGpuOperationMode sets the GOM of the device. GOM changes only take effect
+// after a reboot, so rebootPending reports whether the device is now waiting
+// for one to switch to the requested mode. Requires root.
+func (gpu *Device) SetGpuOperationMode(mode GpuOperationMode) (rebootPending bool, err error) {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetGpuOperationMode"); err != nil {
+		return false, err
+	}
+
+	result = C.nvmlDeviceSetGpuOperationMode(gpu.handle(), C.nvmlGpuOperationMode_t(mode))
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceSetGpuOperationMode", result)
+	}
+
+	current, pending, err := gpu.GpuOperationMode()
+	if err != nil {
+		return false, err
+	}
+
+	return current != pending, nil
+}
+
+// RebootRequired reports whether the device has configuration changes that
+// only take effect after the next reboot, i.e. a pending GOM or ECC mode
+// change, or retired pages pending retirement. Settings the device doesn't
+// support are not taken into account; any other failure is returned.
+func (gpu *Device) RebootRequired() (bool, error) {
+	if current, pending, err := gpu.GpuOperationMode(); err != nil {
+		if !errors.Is(err, ErrNotSupported) {
+			return false, err
+		}
+	} else if current != pending {
+		return true, nil
+	}
+
+	if current, pending, err := gpu.EccMode(); err != nil {
+		if !errors.Is(err, ErrNotSupported) {
+			return false, err
+		}
+	} else if current != pending {
+		return true, nil
+	}
+
+	if pending, err := gpu.RetiredPagesPendingStatus(); err != nil {
+		if !errors.Is(err, ErrNotSupported) {
+			return false, err
+		}
+	} else if pending {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// RestrictedAPI is a group of NVML APIs whose use can be restricted to root
+type RestrictedAPI int
+
+const (
+	RestrictedAPISetApplicationClocks RestrictedAPI = C.NVML_RESTRICTED_API_SET_APPLICATION_CLOCKS
+	RestrictedAPISetAutoBoostedClocks RestrictedAPI = C.NVML_RESTRICTED_API_SET_AUTO_BOOSTED_CLOCKS
+)
+
+func (a RestrictedAPI) String() string {
+	switch a {
+	case RestrictedAPISetApplicationClocks:
+		return "SetApplicationClocks"
+	case RestrictedAPISetAutoBoostedClocks:
+		return "SetAutoBoostedClocks"
+	}
+
+	return fmt.Sprintf("RestrictedAPI(%d)", int(a))
+}
+
+// APIRestriction reports whether the given APIs are restricted to root on the
+// device
+func (gpu *Device) APIRestriction(api RestrictedAPI) (bool, error) {
+	var result C.nvmlReturn_t
+	var crestricted C.nvmlEnableState_t
+
+	if err := requireFunction("nvmlDeviceGetAPIRestriction"); err != nil {
+		return false, err
+	}
+
+	result = C.nvmlDeviceGetAPIRestriction(gpu.handle(), C.nvmlRestrictedAPI_t(api), &crestricted)
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceGetAPIRestriction", result)
+	}
+
+	return crestricted == C.NVML_FEATURE_ENABLED, nil
+}
+
+// SetAPIRestriction restricts the given APIs to root, or allows any user to
+// call them. Requires root.
+func (gpu *Device) SetAPIRestriction(api RestrictedAPI, restricted bool) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetAPIRestriction"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceSetAPIRestriction(gpu.handle(), C.nvmlRestrictedAPI_t(api), enableState(restricted))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetAPIRestriction", result)
+	}
+
+	return nil
+}
+
+// DisplayMode reports whether a physical display (e.g. a monitor) is
+// currently connected to any of the device's connectors
+func (gpu *Device) DisplayMode() (bool, error) {
+	var result C.nvmlReturn_t
+	var cdisplay C.nvmlEnableState_t
+
+	if err := requireFunction("nvmlDeviceGetDisplayMode"); err != nil {
+		return false, err
+	}
+
+	result = C.nvmlDeviceGetDisplayMode(gpu.handle(), &cdisplay)
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceGetDisplayMode", result)
+	}
+
+	return cdisplay == C.NVML_FEATURE_ENABLED, nil
+}
+
+// DisplayActive reports whether a display is initialized on the device, i.e.
+// whether X or another display server has memory allocated on it, even if no
+// monitor is connected
+func (gpu *Device) DisplayActive() (bool, error) {
+	var result C.nvmlReturn_t
+	var cactive C.nvmlEnableState_t
+
+	if err := requireFunction("nvmlDeviceGetDisplayActive"); err != nil {
+		return false, err
+	}
+
+	result = C.nvmlDeviceGetDisplayActive(gpu.handle(), &cactive)
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceGetDisplayActive", result)
+	}
+
+	return cactive == C.NVML_FEATURE_ENABLED, nil
+}
+
+// Go correspondent of the C.nvmlPciInfo_t struct
+type PciInfo struct {
+	BusID          string // domain:bus:device.function, e.g. "0000:81:00.0"
+	Domain         uint
+	Bus            uint
+	Device         uint
+	PciDeviceID    uint32 // combined 16-bit device id and 16-bit vendor id
+	PciSubSystemID uint32
+}
+
+// PciInfo returns the PCI attributes of the device
+func (gpu *Device) PciInfo() (PciInfo, error) {
+	var result C.nvmlReturn_t
+	var cpciinfo C.nvmlPciInfo_t
+	var pciinfo PciInfo
+
+	if err := requireFunction("nvmlDeviceGetPciInfo"); err != nil {
+		return pciinfo, err
+	}
+
+	result = C.nvmlDeviceGetPciInfo(gpu.handle(), &cpciinfo)
+	if result != C.NVML_SUCCESS {
+		return pciinfo, newNVMLError("nvmlDeviceGetPciInfo", result)
+	}
+
+	return newPciInfo(&cpciinfo), nil
+}
+
+// newPciInfo converts a C.nvmlPciInfo_t struct to a PciInfo
+func newPciInfo(cpciinfo *C.nvmlPciInfo_t) PciInfo {
+	return PciInfo{
+		BusID:          strndup(&cpciinfo.busId[0], C.NVML_DEVICE_PCI_BUS_ID_BUFFER_SIZE),
+		Domain:         uint(cpciinfo.domain),
+		Bus:            uint(cpciinfo.bus),
+		Device:         uint(cpciinfo.device),
+		PciDeviceID:    uint32(cpciinfo.pciDeviceId),
+		PciSubSystemID: uint32(cpciinfo.pciSubSystemId),
+	}
+}
+
+// cPciInfo converts a PciInfo back to the C.nvmlPciInfo_t struct NVML expects
+// in functions that take a PCI address instead of a device handle
+func (pciinfo PciInfo) cPciInfo() C.nvmlPciInfo_t {
+	var cpciinfo C.nvmlPciInfo_t
+
+	for i := 0; i < len(pciinfo.BusID) && i < C.NVML_DEVICE_PCI_BUS_ID_BUFFER_SIZE-1; i++ {
+		cpciinfo.busId[i] = C.char(pciinfo.BusID[i])
+	}
+	cpciinfo.domain = C.uint(pciinfo.Domain)
+	cpciinfo.bus = C.uint(pciinfo.Bus)
+	cpciinfo.device = C.uint(pciinfo.Device)
+	cpciinfo.pciDeviceId = C.uint(pciinfo.PciDeviceID)
+	cpciinfo.pciSubSystemId = C.uint(pciinfo.PciSubSystemID)
+
+	return cpciinfo
+}
+
+// BridgeChipType is the type of a bridge chip on a multi-GPU board
+type BridgeChipType int
+
+const (
+	BridgeChipPLX  BridgeChipType = C.NVML_BRIDGE_CHIP_PLX
+	BridgeChipBRO4 BridgeChipType = C.NVML_BRIDGE_CHIP_BRO4
+)
+
+func (t BridgeChipType) String() string {
+	switch t {
+	case BridgeChipPLX:
+		return "PLX"
+	case BridgeChipBRO4:
+		return "BRO4"
+	}
+
+	return fmt.Sprintf("BridgeChipType(%d)", int(t))
+}
+
+// Go correspondent of the C.nvmlBridgeChipInfo_t struct. A FwVersion of 0
+// means the firmware version is unavailable.
+type BridgeChipInfo struct {
+	Type      BridgeChipType
+	FwVersion uint
+}
+
+// BridgeChipInfo returns the hierarchy of bridge chips on the device's board.
+// The bridge closest to the device comes first, followed by its parent, and
+// so forth.
+func (gpu *Device) BridgeChipInfo() ([]BridgeChipInfo, error) {
+	var result C.nvmlReturn_t
+	var chierarchy C.nvmlBridgeChipHierarchy_t
+	var bridges []BridgeChipInfo
+
+	if err := requireFunction("nvmlDeviceGetBridgeChipInfo"); err != nil {
+		return bridges, err
+	}
+
+	result = C.nvmlDeviceGetBridgeChipInfo(gpu.handle(), &chierarchy)
+	if result != C.NVML_SUCCESS {
+		return bridges, newNVMLError("nvmlDeviceGetBridgeChipInfo", result)
+	}
+
+	for i := 0; i < int(chierarchy.bridgeCount); i++ {
+		cinfo := chierarchy.bridgeChipInfo[i]
+		bridges = append(bridges, BridgeChipInfo{
+			Type:      BridgeChipType(cinfo._type),
+			FwVersion: uint(cinfo.fwVersion),
+		})
+	}
+
+	return bridges, nil
 }
 
 // Go correspondent of the C.nvmlMemory_t struct. Memory in bytes
@@ -331,9 +1340,13 @@ func (gpu *Device) MemoryInfo() (NVMLMemory, error) {
 	var cmeminfo C.nvmlMemory_t
 	var meminfo NVMLMemory
 
-	result = C.nvmlDeviceGetMemoryInfo(gpu.nvmldevice, &cmeminfo)
+	if err := requireFunction("nvmlDeviceGetMemoryInfo"); err != nil {
+		return meminfo, err
+	}
+
+	result = C.nvmlDeviceGetMemoryInfo(gpu.handle(), &cmeminfo)
 	if result != C.NVML_SUCCESS {
-		return meminfo, errors.New("GetPowerState returned error")
+		return meminfo, newNVMLError("nvmlDeviceGetMemoryInfo", result)
 	}
 
 	meminfo.Free = uint64(cmeminfo.free)
@@ -364,50 +1377,120 @@ func (gpu *Device) Error(cerror C.nvmlReturn_t) error {
 func nvmlDeviceGetCount() (int, error) {
 	var count C.uint
 
+	if err := requireFunction("nvmlDeviceGetCount"); err != nil {
+		return -1, err
+	}
+
 	result := C.nvmlDeviceGetCount(&count)
 	if result != C.NVML_SUCCESS {
-		return -1, errors.New("nvmlDeviceGetCount failed")
+		return -1, newNVMLError("nvmlDeviceGetCount", result)
 	}
 
 	return int(count), nil
 }
 
-// GetAllGPUs will return a slice of type Device for all NVML devices present on
-// the host system
-func GetAllGPUs() ([]Device, error) {
-	var devices []Device
+// maxEnumerationWorkers bounds the number of devices GetAllGPUs constructs
+// concurrently.
+const maxEnumerationWorkers = 8
+
+// GetAllGPUs will return a slice of type *Device for all NVML devices present
+// on the host system
+//
+// Devices are constructed in parallel, since each one needs several NVML calls
+// to fetch its static properties, but are returned in NVML index order. A
+// device that can't be constructed is left out and its error reported, joined
+// with those of any others, so one broken GPU doesn't hide the healthy ones.
+func GetAllGPUs() ([]*Device, error) {
+	var devices []*Device
 	cdevices, err := getAllDevices()
 	if err != nil {
 		return devices, err
 	}
 
-	for _, cdevice := range cdevices {
-		device, err := NewDevice(cdevice)
+	results := make([]*Device, len(cdevices))
+	errs := make([]error, len(cdevices))
+	sem := make(chan struct{}, maxEnumerationWorkers)
+	var wg sync.WaitGroup
+
+	for i, cdevice := range cdevices {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, cdevice C.nvmlDevice_t) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			device, err := NewDevice(cdevice)
+			if err != nil {
+				errs[i] = fmt.Errorf("device %d: %w", i, err)
+				return
+			}
+			results[i] = device
+		}(i, cdevice)
+	}
+	wg.Wait()
+
+	for _, device := range results {
+		if device != nil {
+			devices = append(devices, device)
+		}
+	}
+
+	return devices, errors.Join(errs...)
+}
+
+// Devices iterates over the NVML devices present on the host system, in index
+// order. Unlike GetAllGPUs, each device's handle is only fetched and its
+// Device constructed when the loop reaches it, so breaking out early skips the
+// rest. A device that can't be constructed yields a nil *Device and its error;
+// the loop may carry on with the next one.
+func Devices() iter.Seq2[*Device, error] {
+	return func(yield func(*Device, error) bool) {
+		count, err := nvmlDeviceGetCount()
+		if err == nil {
+			err = requireFunction("nvmlDeviceGetHandleByIndex")
+		}
 		if err != nil {
-			break
+			yield(nil, err)
+			return
 		}
 
-		devices = append(devices, *device)
-	}
+		for i := 0; i < count; i++ {
+			var cdevice C.nvmlDevice_t
 
-	return devices, nil
+			result := C.nvmlDeviceGetHandleByIndex(C.uint(i), &cdevice)
+			if result != C.NVML_SUCCESS {
+				if !yield(nil, newNVMLError("nvmlDeviceGetHandleByIndex", result)) {
+					return
+				}
+				continue
+			}
+
+			if !yield(NewDevice(cdevice)) {
+				return
+			}
+		}
+	}
 }
 
 // getAllDevices returns an array of nvmlDevice_t structs representing all GPU
-// devices in the system.
+// devices in the system. NVML failures are returned as *NVMLError.
 func getAllDevices() ([]C.nvmlDevice_t, error) {
 	var devices []C.nvmlDevice_t
 
+	if err := requireFunction("nvmlDeviceGetHandleByIndex"); err != nil {
+		return devices, err
+	}
+
 	device_count, err := nvmlDeviceGetCount()
 	if err != nil {
-		log.Fatal(err)
+		return devices, err
 	}
 
 	for i := 0; i < device_count; i++ {
 		var device C.nvmlDevice_t
 		result := C.nvmlDeviceGetHandleByIndex(C.uint(i), &device)
 		if result != C.NVML_SUCCESS {
-			return devices, errors.New("nvmlDeviceGetHandleByIndex returns error")
+			return devices, newNVMLError("nvmlDeviceGetHandleByIndex", result)
 		}
 
 		devices = append(devices, device)
@@ -5,3 +5,69 @@ import (
 )
 
 func TestIndex(t *testing.T) { testIndex(t) }
+
+func TestPstateString(t *testing.T) {
+	var tests = []struct {
+		p Pstate
+		s string
+	}{
+		{P0, "P0"},
+		{P8, "P8"},
+		{P15, "P15"},
+		{PstateUnknown, "Unknown"},
+		{Pstate(20), "Pstate(20)"},
+	}
+
+	for _, ts := range tests {
+		if ts.p.String() != ts.s {
+			t.Errorf("Pstate(%d).String() = %s, expected %s", int(ts.p), ts.p.String(), ts.s)
+		}
+	}
+}
+
+func TestBrandString(t *testing.T) {
+	var tests = []struct {
+		b Brand
+		s string
+	}{
+		{BrandTesla, "Tesla"},
+		{BrandGeForce, "GeForce"},
+		{BrandUnknown, "Unknown"},
+		{Brand(100), "Brand(100)"},
+	}
+
+	for _, ts := range tests {
+		if ts.b.String() != ts.s {
+			t.Errorf("Brand(%d).String() = %s, expected %s", int(ts.b), ts.b.String(), ts.s)
+		}
+	}
+}
+
+func TestStaticPropertyString(t *testing.T) {
+	var tests = []struct {
+		p StaticProperty
+		s string
+	}{
+		{PropUUID, "UUID"},
+		{PropPciInfo, "PciInfo"},
+		{StaticProperty(100), "StaticProperty(100)"},
+	}
+
+	for _, ts := range tests {
+		if ts.p.String() != ts.s {
+			t.Errorf("StaticProperty(%d).String() = %s, expected %s", int(ts.p), ts.p.String(), ts.s)
+		}
+	}
+}
+
+func TestHandleRoundTrip(t *testing.T) {
+	const handle = uintptr(0xdeadbeef)
+
+	gpu, err := NewDeviceFromHandle(handle, WithLazyProperties())
+	if err != nil {
+		t.Fatalf("NewDeviceFromHandle: %s", err)
+	}
+	if gpu.Handle() != handle {
+		t.Errorf("Handle() = %#x, expected %#x", gpu.Handle(), handle)
+	}
+}
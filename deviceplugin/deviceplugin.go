@@ -0,0 +1,195 @@
+// Package deviceplugin maps NVML devices to Kubernetes device plugin devices,
+// for building device plugins on top of the nvml package.
+//
+// Devices are identified by their UUID, which is also what the NVIDIA container
+// runtime expects in NVIDIA_VISIBLE_DEVICES.
+//
+// Only whole GPUs are mapped. MIG devices can't be enumerated through the
+// vendored NVML API, which predates MIG, so MIG slices aren't advertised.
+package deviceplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	nvml "github.com/davidr/go-nvml"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+)
+
+// sysfsPCIDevices is where the kernel exposes PCI devices
+var sysfsPCIDevices = "/sys/bus/pci/devices"
+
+// numaNode returns the NUMA node of the PCI device with the given bus ID, or
+// -1 if it isn't known
+func numaNode(busID nvml.PCIBusID) int64 {
+	b, err := os.ReadFile(filepath.Join(sysfsPCIDevices, busID.String(), "numa_node"))
+	if err != nil {
+		return -1
+	}
+
+	node, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return -1
+	}
+
+	return node
+}
+
+// Device returns the device plugin Device for gpu, healthy and with its NUMA
+// node as topology hint if the kernel reports one
+func Device(gpu *nvml.Device) (*pluginapi.Device, error) {
+	uuid, err := gpu.UUID()
+	if err != nil {
+		return nil, err
+	}
+
+	device := &pluginapi.Device{ID: uuid, Health: pluginapi.Healthy}
+
+	if pciinfo, err := gpu.PciInfo(); err == nil {
+		if busID, err := pciinfo.PCIBusID(); err == nil {
+			if node := numaNode(busID); node >= 0 {
+				device.Topology = &pluginapi.TopologyInfo{
+					Nodes: []*pluginapi.NUMANode{{ID: node}},
+				}
+			}
+		}
+	}
+
+	return device, nil
+}
+
+// Devices returns the device plugin Devices for gpus. Devices whose UUID
+// can't be read are left out, and reported in the error.
+func Devices(gpus []*nvml.Device) ([]*pluginapi.Device, error) {
+	var devices []*pluginapi.Device
+	var errs []error
+
+	for i, gpu := range gpus {
+		device, err := Device(gpu)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("device %d: %w", i, err))
+			continue
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, errors.Join(errs...)
+}
+
+// HealthEvent reports that a device became unhealthy because of an XID error,
+// or healthy again
+type HealthEvent struct {
+	ID     string   // the device's UUID
+	Health string   // pluginapi.Healthy or pluginapi.Unhealthy
+	Xid    nvml.Xid // the XID that made the device unhealthy; 0 when it recovers
+}
+
+// IsCritical reports whether an XID error should mark the device unhealthy.
+// XIDs caused by applications, and those unknown to the nvml package, don't.
+func IsCritical(xid nvml.Xid) bool {
+	switch xid.Category() {
+	case nvml.XidCategoryMemory, nvml.XidCategoryHardware, nvml.XidCategoryInterconnect:
+		return true
+	}
+
+	return false
+}
+
+// RecoveryInterval is how long a device must go without critical XID errors
+// before WatchHealth checks whether it recovered
+var RecoveryInterval = time.Minute
+
+// recovered reports whether a device marked unhealthy can be used again: it
+// answers queries and has no pending reboot, e.g. for retiring pages
+func recovered(gpu *nvml.Device) bool {
+	if !gpu.IsHealthy() {
+		return false
+	}
+
+	pending, err := gpu.RebootRequired()
+	return err == nil && !pending
+}
+
+// healthTracker keeps the devices WatchHealth reported unhealthy. It's only
+// used from WatchHealth's goroutine.
+type healthTracker struct {
+	unhealthy map[string]unhealthyDevice // by UUID
+}
+
+type unhealthyDevice struct {
+	gpu  *nvml.Device
+	last time.Time // time of the last critical XID
+}
+
+// markUnhealthy records a critical XID on the device with the given UUID
+func (t *healthTracker) markUnhealthy(uuid string, gpu *nvml.Device, now time.Time) {
+	if t.unhealthy == nil {
+		t.unhealthy = make(map[string]unhealthyDevice)
+	}
+	t.unhealthy[uuid] = unhealthyDevice{gpu: gpu, last: now}
+}
+
+// recheck returns the UUIDs of the unhealthy devices that had no critical XID
+// for the RecoveryInterval and are found recovered by probe, and forgets them
+func (t *healthTracker) recheck(now time.Time, probe func(*nvml.Device) bool) []string {
+	var ids []string
+	for uuid, device := range t.unhealthy {
+		if now.Sub(device.last) < RecoveryInterval || !probe(device.gpu) {
+			continue
+		}
+		ids = append(ids, uuid)
+		delete(t.unhealthy, uuid)
+	}
+
+	return ids
+}
+
+// WatchHealth watches all GPUs for XID errors until the context is cancelled
+// and calls onEvent for each critical one, so the plugin can mark the device
+// unhealthy and send an updated ListAndWatch response. Unhealthy devices are
+// rechecked every RecoveryInterval, and reported healthy again once they
+// answer queries and need no reboot.
+func WatchHealth(ctx context.Context, onEvent func(HealthEvent)) error {
+	events, err := nvml.WatchXidErrors(ctx)
+	if err != nil {
+		return err
+	}
+
+	var tracker healthTracker
+	go func() {
+		ticker := time.NewTicker(RecoveryInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if event.Device == nil || !IsCritical(event.Xid) {
+					continue
+				}
+
+				uuid, err := event.Device.UUID()
+				if err != nil {
+					continue
+				}
+
+				tracker.markUnhealthy(uuid, event.Device, event.Time)
+				onEvent(HealthEvent{ID: uuid, Health: pluginapi.Unhealthy, Xid: event.Xid})
+			case now := <-ticker.C:
+				for _, uuid := range tracker.recheck(now, recovered) {
+					onEvent(HealthEvent{ID: uuid, Health: pluginapi.Healthy})
+				}
+			}
+		}
+	}()
+
+	return nil
+}
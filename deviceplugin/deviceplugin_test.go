@@ -0,0 +1,97 @@
+package deviceplugin
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	nvml "github.com/davidr/go-nvml"
+)
+
+func TestIsCritical(t *testing.T) {
+	var tests = []struct {
+		xid      nvml.Xid
+		critical bool
+	}{
+		{nvml.XidMemoryPageFault, false},
+		{nvml.XidDoubleBitEccError, true},
+		{nvml.XidFallenOffTheBus, true},
+		{nvml.XidNvLinkError, true},
+		{nvml.Xid(12345), false},
+	}
+
+	for _, ts := range tests {
+		if IsCritical(ts.xid) != ts.critical {
+			t.Errorf("IsCritical(%s) = %v, expected %v", ts.xid, !ts.critical, ts.critical)
+		}
+	}
+}
+
+func TestNumaNode(t *testing.T) {
+	dir := t.TempDir()
+	sysfsPCIDevices = dir
+
+	for busID, node := range map[string]string{"0000:04:00.0": "1\n", "0000:05:00.0": "-1\n"} {
+		os.MkdirAll(filepath.Join(dir, busID), 0755)
+		os.WriteFile(filepath.Join(dir, busID, "numa_node"), []byte(node), 0644)
+	}
+
+	var tests = []struct {
+		busID string
+		node  int64
+	}{
+		{"0000:04:00.0", 1},
+		{"0000:05:00.0", -1},
+		{"00000000:06:00.0", -1},
+		{"00000000:04:00.0", 1},
+	}
+
+	for _, ts := range tests {
+		busID, err := nvml.ParsePCIBusID(ts.busID)
+		if err != nil {
+			t.Fatalf("ParsePCIBusID(%s): %s", ts.busID, err)
+		}
+		if node := numaNode(busID); node != ts.node {
+			t.Errorf("numaNode(%s) = %d, expected %d", ts.busID, node, ts.node)
+		}
+	}
+}
+
+func TestHealthRecovery(t *testing.T) {
+	start := time.Now()
+	gpus := map[string]*nvml.Device{"GPU-a": {}, "GPU-b": {}, "GPU-c": {}}
+	healthy := map[*nvml.Device]bool{gpus["GPU-a"]: true, gpus["GPU-b"]: false, gpus["GPU-c"]: true}
+
+	var tracker healthTracker
+	tracker.markUnhealthy("GPU-a", gpus["GPU-a"], start)
+	tracker.markUnhealthy("GPU-b", gpus["GPU-b"], start)
+	tracker.markUnhealthy("GPU-c", gpus["GPU-c"], start.Add(RecoveryInterval/2))
+
+	probe := func(gpu *nvml.Device) bool { return healthy[gpu] }
+
+	var tests = []struct {
+		at        time.Duration
+		recovered []string
+	}{
+		{RecoveryInterval / 2, nil},
+		{RecoveryInterval, []string{"GPU-a"}},
+		{RecoveryInterval, nil},
+		{RecoveryInterval * 3 / 2, []string{"GPU-c"}},
+		{RecoveryInterval * 10, nil},
+	}
+
+	for _, ts := range tests {
+		ids := tracker.recheck(start.Add(ts.at), probe)
+		sort.Strings(ids)
+		if !reflect.DeepEqual(ids, ts.recovered) {
+			t.Errorf("recheck after %s = %v, expected %v", ts.at, ids, ts.recovered)
+		}
+	}
+
+	if _, ok := tracker.unhealthy["GPU-b"]; !ok {
+		t.Errorf("device that didn't recover was forgotten")
+	}
+}
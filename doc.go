@@ -0,0 +1,31 @@
+// Package nvml provides bindings to NVIDIA's NVML library.
+//
+// # Concurrency
+//
+// NVML itself is thread-safe, and so is this package: Device, Unit and
+// EventSet methods may be called from multiple goroutines at once, including
+// on the same Device. NVMLInit and NVMLShutdown are serialized and may also be
+// called concurrently, but devices must not be queried after the last session
+// has been shut down.
+//
+// The exceptions are calls that act on the calling OS thread, such as
+// Device.SetCpuAffinity, which should be made with the goroutine locked to
+// its thread.
+//
+// # NVML versions
+//
+// The package is built against the vendored nvml.h and links the NVML library
+// directly. Older drivers lack some of the functions declared in it, so every
+// wrapper checks that the driver implements its function before calling it and
+// fails with ErrFunctionNotFound otherwise, and the getter tables look their
+// functions up by name on first use. MissingFunctions lists the functions the
+// installed driver lacks, and HasFunction checks for one, so programs can tell
+// up front whether a driver is new enough.
+//
+// This relies on the loader binding functions lazily, which it does by default
+// on Linux but not when the program is linked with -z now or run with
+// LD_BIND_NOW set. Where missing functions keep the program from loading at
+// all, such as on Windows, build with the nvml_legacy tag to compile out the
+// drain functions, the newest in the header. They keep their signatures but
+// always fail with ErrFunctionNotFound.
+package nvml
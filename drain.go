@@ -0,0 +1,96 @@
+//go:build !nvml_legacy
+// +build !nvml_legacy
+
+package nvml
+
+// See https://docs.nvidia.com/deploy/nvml-api/group__nvmlGpuMgmt.html
+//
+// These functions take the PCI address of a GPU rather than a Device, since
+// they're used on GPUs that are being removed or haven't been discovered yet.
+// They are Linux only and, except for QueryDrainState, require root. A GPU is
+// hot-swapped by draining it, removing it, and then rediscovering it.
+//
+// They are the newest functions in the vendored header. Like every wrapper,
+// each checks that the driver implements it first, and building with the
+// nvml_legacy tag compiles them out, see drain_legacy.go.
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+// ModifyDrainState puts the GPU at the given PCI address into, or takes it out
+// of, the draining state, in which it accepts no new processes.
+func ModifyDrainState(pciinfo PciInfo, draining bool) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceModifyDrainState"); err != nil {
+		return err
+	}
+
+	cpciinfo := pciinfo.cPciInfo()
+	result = C.nvmlDeviceModifyDrainState(&cpciinfo, enableState(draining))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceModifyDrainState", result)
+	}
+
+	return nil
+}
+
+// QueryDrainState reports whether the GPU at the given PCI address is draining
+func QueryDrainState(pciinfo PciInfo) (bool, error) {
+	var result C.nvmlReturn_t
+	var cstate C.nvmlEnableState_t
+
+	if err := requireFunction("nvmlDeviceQueryDrainState"); err != nil {
+		return false, err
+	}
+
+	cpciinfo := pciinfo.cPciInfo()
+	result = C.nvmlDeviceQueryDrainState(&cpciinfo, &cstate)
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceQueryDrainState", result)
+	}
+
+	return cstate == C.NVML_FEATURE_ENABLED, nil
+}
+
+// RemoveGpu removes the GPU at the given PCI address from the view of NVML
+// and the kernel driver. It fails if any process, including the persistence
+// daemon, is still attached to it. Device handles of GPUs enumerated after
+// the removed one become invalid.
+func RemoveGpu(pciinfo PciInfo) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceRemoveGpu"); err != nil {
+		return err
+	}
+
+	cpciinfo := pciinfo.cPciInfo()
+	result = C.nvmlDeviceRemoveGpu(&cpciinfo)
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceRemoveGpu", result)
+	}
+
+	return nil
+}
+
+// DiscoverGpus asks the kernel driver to rediscover previously removed GPUs in
+// the part of the PCI tree given by the domain, bus and device of pciinfo.
+// A zero PciInfo searches the whole tree. Afterwards, existing device handles
+// are no longer guaranteed to be valid.
+func DiscoverGpus(pciinfo PciInfo) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceDiscoverGpus"); err != nil {
+		return err
+	}
+
+	cpciinfo := pciinfo.cPciInfo()
+	result = C.nvmlDeviceDiscoverGpus(&cpciinfo)
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceDiscoverGpus", result)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+//go:build nvml_legacy
+// +build nvml_legacy
+
+package nvml
+
+// The nvml_legacy build tag compiles out the drain functions, the newest in
+// the vendored header, for drivers that lack them. Their wrappers keep their
+// signatures, so callers build either way, but always fail with
+// ErrFunctionNotFound. Elsewhere the wrappers check for their function before
+// calling it, but that doesn't help where a missing function stops the program
+// from loading at all, e.g. on Windows, where nvml.dll is linked through its
+// import library, or when functions are bound eagerly.
+
+// ModifyDrainState always fails with ErrFunctionNotFound
+func ModifyDrainState(pciinfo PciInfo, draining bool) error {
+	return functionNotFound("nvmlDeviceModifyDrainState")
+}
+
+// QueryDrainState always fails with ErrFunctionNotFound
+func QueryDrainState(pciinfo PciInfo) (bool, error) {
+	return false, functionNotFound("nvmlDeviceQueryDrainState")
+}
+
+// RemoveGpu always fails with ErrFunctionNotFound
+func RemoveGpu(pciinfo PciInfo) error {
+	return functionNotFound("nvmlDeviceRemoveGpu")
+}
+
+// DiscoverGpus always fails with ErrFunctionNotFound
+func DiscoverGpus(pciinfo PciInfo) error {
+	return functionNotFound("nvmlDeviceDiscoverGpus")
+}
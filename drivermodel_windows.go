@@ -0,0 +1,77 @@
+//go:build windows
+// +build windows
+
+package nvml
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+// DriverModel is the Windows driver model of a device
+type DriverModel int
+
+const (
+	// DriverModelWDDM treats the GPU as a display device
+	DriverModelWDDM DriverModel = C.NVML_DRIVER_WDDM
+	// DriverModelWDM treats the GPU as a generic device. Also known as TCC,
+	// this is the recommended model for compute.
+	DriverModelWDM DriverModel = C.NVML_DRIVER_WDM
+)
+
+func (m DriverModel) String() string {
+	switch m {
+	case DriverModelWDDM:
+		return "WDDM"
+	case DriverModelWDM:
+		return "TCC"
+	}
+
+	return fmt.Sprintf("DriverModel(%d)", int(m))
+}
+
+// Flags for SetDriverModel
+const (
+	FlagDefault uint = C.nvmlFlagDefault
+	// FlagForce switches to WDDM even if no display is attached to the device
+	FlagForce uint = C.nvmlFlagForce
+)
+
+// DriverModel returns the current driver model of the device, and the one
+// it will switch to after the next reboot. Windows only.
+func (gpu *Device) DriverModel() (current, pending DriverModel, err error) {
+	var result C.nvmlReturn_t
+	var ccurrent, cpending C.nvmlDriverModel_t
+
+	if err := requireFunction("nvmlDeviceGetDriverModel"); err != nil {
+		return 0, 0, err
+	}
+
+	result = C.nvmlDeviceGetDriverModel(gpu.handle(), &ccurrent, &cpending)
+	if result != C.NVML_SUCCESS {
+		return 0, 0, newNVMLError("nvmlDeviceGetDriverModel", result)
+	}
+
+	return DriverModel(ccurrent), DriverModel(cpending), nil
+}
+
+// SetDriverModel sets the driver model of the device. The change takes effect
+// after the next reboot. Windows only, requires administrator.
+func (gpu *Device) SetDriverModel(model DriverModel, flags uint) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetDriverModel"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceSetDriverModel(gpu.handle(), C.nvmlDriverModel_t(model), C.uint(flags))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetDriverModel", result)
+	}
+
+	return nil
+}
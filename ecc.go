@@ -0,0 +1,277 @@
+package nvml
+
+// See https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+// EccMode returns whether ECC is currently enabled on the device, and whether
+// it will be enabled after the next reboot.
+func (gpu *Device) EccMode() (current, pending bool, err error) {
+	var result C.nvmlReturn_t
+	var ccurrent, cpending C.nvmlEnableState_t
+
+	if err := requireFunction("nvmlDeviceGetEccMode"); err != nil {
+		return false, false, err
+	}
+
+	result = C.nvmlDeviceGetEccMode(gpu.handle(), &ccurrent, &cpending)
+	if result != C.NVML_SUCCESS {
+		return false, false, newNVMLError("nvmlDeviceGetEccMode", result)
+	}
+
+	return ccurrent == C.NVML_FEATURE_ENABLED, cpending == C.NVML_FEATURE_ENABLED, nil
+}
+
+// SetEccMode enables or disables ECC on the device. The change only takes
+// effect after the next reboot. Requires root.
+func (gpu *Device) SetEccMode(enabled bool) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetEccMode"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceSetEccMode(gpu.handle(), enableState(enabled))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetEccMode", result)
+	}
+
+	return nil
+}
+
+// MemoryErrorType is the type of a memory error. For ECC errors, corrected
+// errors are single bit errors and uncorrected errors double bit errors.
+type MemoryErrorType int
+
+const (
+	MemoryErrorCorrected   MemoryErrorType = C.NVML_MEMORY_ERROR_TYPE_CORRECTED
+	MemoryErrorUncorrected MemoryErrorType = C.NVML_MEMORY_ERROR_TYPE_UNCORRECTED
+)
+
+func (t MemoryErrorType) String() string {
+	switch t {
+	case MemoryErrorCorrected:
+		return "Corrected"
+	case MemoryErrorUncorrected:
+		return "Uncorrected"
+	}
+
+	return fmt.Sprintf("MemoryErrorType(%d)", int(t))
+}
+
+// EccCounterType selects which ECC error counter to read. Volatile counters are
+// reset each time the driver loads, aggregate counters persist for the lifetime
+// of the device.
+type EccCounterType int
+
+const (
+	EccCounterVolatile  EccCounterType = C.NVML_VOLATILE_ECC
+	EccCounterAggregate EccCounterType = C.NVML_AGGREGATE_ECC
+)
+
+func (t EccCounterType) String() string {
+	switch t {
+	case EccCounterVolatile:
+		return "Volatile"
+	case EccCounterAggregate:
+		return "Aggregate"
+	}
+
+	return fmt.Sprintf("EccCounterType(%d)", int(t))
+}
+
+// Go correspondent of the C.nvmlEccErrorCounts_t struct
+type EccErrorCounts struct {
+	L1Cache      uint64
+	L2Cache      uint64
+	DeviceMemory uint64
+	RegisterFile uint64
+}
+
+// TotalEccErrors returns the total number of ECC errors of the given type
+// counted by the given counter, for all memory locations on the device.
+func (gpu *Device) TotalEccErrors(errorType MemoryErrorType, counterType EccCounterType) (uint64, error) {
+	var result C.nvmlReturn_t
+	var ccount C.ulonglong
+
+	if err := requireFunction("nvmlDeviceGetTotalEccErrors"); err != nil {
+		return 0, err
+	}
+
+	result = C.nvmlDeviceGetTotalEccErrors(gpu.handle(), C.nvmlMemoryErrorType_t(errorType), C.nvmlEccCounterType_t(counterType), &ccount)
+	if result != C.NVML_SUCCESS {
+		return 0, newNVMLError("nvmlDeviceGetTotalEccErrors", result)
+	}
+
+	return uint64(ccount), nil
+}
+
+// DetailedEccErrors returns the number of ECC errors of the given type counted
+// by the given counter, broken down by memory location. Not every location is
+// reported on every GPU family; see also MemoryErrorCounter.
+func (gpu *Device) DetailedEccErrors(errorType MemoryErrorType, counterType EccCounterType) (EccErrorCounts, error) {
+	var result C.nvmlReturn_t
+	var ccounts C.nvmlEccErrorCounts_t
+	var counts EccErrorCounts
+
+	if err := requireFunction("nvmlDeviceGetDetailedEccErrors"); err != nil {
+		return counts, err
+	}
+
+	result = C.nvmlDeviceGetDetailedEccErrors(gpu.handle(), C.nvmlMemoryErrorType_t(errorType), C.nvmlEccCounterType_t(counterType), &ccounts)
+	if result != C.NVML_SUCCESS {
+		return counts, newNVMLError("nvmlDeviceGetDetailedEccErrors", result)
+	}
+
+	counts.L1Cache = uint64(ccounts.l1Cache)
+	counts.L2Cache = uint64(ccounts.l2Cache)
+	counts.DeviceMemory = uint64(ccounts.deviceMemory)
+	counts.RegisterFile = uint64(ccounts.registerFile)
+
+	return counts, nil
+}
+
+// MemoryLocation is a memory location on the device that memory errors can be
+// counted for
+type MemoryLocation int
+
+const (
+	MemoryLocationL1Cache       MemoryLocation = C.NVML_MEMORY_LOCATION_L1_CACHE
+	MemoryLocationL2Cache       MemoryLocation = C.NVML_MEMORY_LOCATION_L2_CACHE
+	MemoryLocationDeviceMemory  MemoryLocation = C.NVML_MEMORY_LOCATION_DEVICE_MEMORY
+	MemoryLocationRegisterFile  MemoryLocation = C.NVML_MEMORY_LOCATION_REGISTER_FILE
+	MemoryLocationTextureMemory MemoryLocation = C.NVML_MEMORY_LOCATION_TEXTURE_MEMORY
+	MemoryLocationTextureShm    MemoryLocation = C.NVML_MEMORY_LOCATION_TEXTURE_SHM
+)
+
+func (l MemoryLocation) String() string {
+	switch l {
+	case MemoryLocationL1Cache:
+		return "L1Cache"
+	case MemoryLocationL2Cache:
+		return "L2Cache"
+	case MemoryLocationDeviceMemory:
+		return "DeviceMemory"
+	case MemoryLocationRegisterFile:
+		return "RegisterFile"
+	case MemoryLocationTextureMemory:
+		return "TextureMemory"
+	case MemoryLocationTextureShm:
+		return "TextureShm"
+	}
+
+	return fmt.Sprintf("MemoryLocation(%d)", int(l))
+}
+
+// MemoryErrorCounter returns the number of memory errors of the given type,
+// counted by the given counter, in a single memory location of the device.
+func (gpu *Device) MemoryErrorCounter(errorType MemoryErrorType, counterType EccCounterType, location MemoryLocation) (uint64, error) {
+	var result C.nvmlReturn_t
+	var ccount C.ulonglong
+
+	if err := requireFunction("nvmlDeviceGetMemoryErrorCounter"); err != nil {
+		return 0, err
+	}
+
+	result = C.nvmlDeviceGetMemoryErrorCounter(gpu.handle(), C.nvmlMemoryErrorType_t(errorType),
+		C.nvmlEccCounterType_t(counterType), C.nvmlMemoryLocation_t(location), &ccount)
+	if result != C.NVML_SUCCESS {
+		return 0, newNVMLError("nvmlDeviceGetMemoryErrorCounter", result)
+	}
+
+	return uint64(ccount), nil
+}
+
+// ClearEccErrorCounts resets the ECC error counters of the given type on the
+// device. Requires root.
+func (gpu *Device) ClearEccErrorCounts(counterType EccCounterType) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceClearEccErrorCounts"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceClearEccErrorCounts(gpu.handle(), C.nvmlEccCounterType_t(counterType))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceClearEccErrorCounts", result)
+	}
+
+	return nil
+}
+
+// PageRetirementCause is the reason a page of device memory was retired
+type PageRetirementCause int
+
+const (
+	PageRetirementMultipleSingleBitEccErrors PageRetirementCause = C.NVML_PAGE_RETIREMENT_CAUSE_MULTIPLE_SINGLE_BIT_ECC_ERRORS
+	PageRetirementDoubleBitEccError          PageRetirementCause = C.NVML_PAGE_RETIREMENT_CAUSE_DOUBLE_BIT_ECC_ERROR
+)
+
+func (c PageRetirementCause) String() string {
+	switch c {
+	case PageRetirementMultipleSingleBitEccErrors:
+		return "MultipleSingleBitEccErrors"
+	case PageRetirementDoubleBitEccError:
+		return "DoubleBitEccError"
+	}
+
+	return fmt.Sprintf("PageRetirementCause(%d)", int(c))
+}
+
+// RetiredPages returns the hardware addresses of the pages retired for the
+// given cause, including pages that are pending retirement. The addresses
+// match those reported in XID 63.
+func (gpu *Device) RetiredPages(cause PageRetirementCause) ([]uint64, error) {
+	var result C.nvmlReturn_t
+	var ccount C.uint
+	var pages []uint64
+
+	if err := requireFunction("nvmlDeviceGetRetiredPages"); err != nil {
+		return pages, err
+	}
+
+	result = C.nvmlDeviceGetRetiredPages(gpu.handle(), C.nvmlPageRetirementCause_t(cause), &ccount, nil)
+	if result == C.NVML_SUCCESS && ccount == 0 {
+		return pages, nil
+	}
+	if result != C.NVML_SUCCESS && result != C.NVML_ERROR_INSUFFICIENT_SIZE {
+		return pages, newNVMLError("nvmlDeviceGetRetiredPages", result)
+	}
+
+	caddresses := make([]C.ulonglong, ccount)
+	result = C.nvmlDeviceGetRetiredPages(gpu.handle(), C.nvmlPageRetirementCause_t(cause), &ccount, &caddresses[0])
+	if result != C.NVML_SUCCESS {
+		return pages, newNVMLError("nvmlDeviceGetRetiredPages", result)
+	}
+
+	for _, address := range caddresses[:ccount] {
+		pages = append(pages, uint64(address))
+	}
+
+	return pages, nil
+}
+
+// RetiredPagesPendingStatus reports whether any pages are pending retirement
+// and need a reboot to be fully retired.
+func (gpu *Device) RetiredPagesPendingStatus() (bool, error) {
+	var result C.nvmlReturn_t
+	var cpending C.nvmlEnableState_t
+
+	if err := requireFunction("nvmlDeviceGetRetiredPagesPendingStatus"); err != nil {
+		return false, err
+	}
+
+	result = C.nvmlDeviceGetRetiredPagesPendingStatus(gpu.handle(), &cpending)
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceGetRetiredPagesPendingStatus", result)
+	}
+
+	return cpending == C.NVML_FEATURE_ENABLED, nil
+}
@@ -0,0 +1,265 @@
+package nvml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Every enum type has a String method returning its name, and a Parse
+// function doing the reverse, so values can be logged, used as metric labels
+// and read from flags or config files. Parsing ignores case. Bitmask types
+// are formatted as the names of their bits joined by "|", e.g.
+// "SwPowerCap|HwSlowdown", and parsed the same way.
+
+// parseEnum returns the value among values whose name is s
+func parseEnum[T fmt.Stringer](kind, s string, values []T) (T, error) {
+	for _, v := range values {
+		if strings.EqualFold(v.String(), s) {
+			return v, nil
+		}
+	}
+
+	var zero T
+	return zero, fmt.Errorf("invalid %s %q", kind, s)
+}
+
+// maskString formats mask as the names of the bits in it, joined by "|". Bits
+// without a name are appended in hex.
+func maskString[T ~uint64 | ~int](mask T, bits []T) string {
+	var names []string
+
+	for _, bit := range bits {
+		if mask&bit == bit {
+			names = append(names, fmt.Sprint(bit))
+			mask &^= bit
+		}
+	}
+	if mask != 0 {
+		names = append(names, fmt.Sprintf("%#x", uint64(mask)))
+	}
+
+	return strings.Join(names, "|")
+}
+
+// parseMask parses the output of maskString. The names of values that aren't
+// single bits, such as the empty and full sets, are accepted too.
+func parseMask[T interface {
+	~uint64 | ~int
+	fmt.Stringer
+}](kind, s string, values []T) (T, error) {
+	var mask T
+
+	for _, part := range strings.Split(s, "|") {
+		part = strings.TrimSpace(part)
+		if v, err := parseEnum(kind, part, values); err == nil {
+			mask |= v
+			continue
+		}
+		if strings.HasPrefix(part, "0x") {
+			if v, err := strconv.ParseUint(part[2:], 16, 64); err == nil {
+				mask |= T(v)
+				continue
+			}
+		}
+
+		return 0, fmt.Errorf("invalid %s %q", kind, s)
+	}
+
+	return mask, nil
+}
+
+var pstates = []Pstate{
+	P0, P1, P2, P3, P4, P5, P6, P7, P8, P9, P10, P11, P12, P13, P14, P15, PstateUnknown,
+}
+
+// ParsePstate parses a performance state name, e.g. "P0"
+func ParsePstate(s string) (Pstate, error) {
+	return parseEnum("performance state", s, pstates)
+}
+
+// ParseReturn parses a return code name, e.g. "NotSupported"
+func ParseReturn(s string) (Return, error) {
+	return parseEnum("return code", s, returns)
+}
+
+var brands = []Brand{BrandUnknown, BrandQuadro, BrandTesla, BrandNVS, BrandGRID, BrandGeForce}
+
+// ParseBrand parses a brand name, e.g. "Tesla"
+func ParseBrand(s string) (Brand, error) {
+	return parseEnum("brand", s, brands)
+}
+
+var temperatureSensors = []TemperatureSensor{TemperatureGPU}
+
+// ParseTemperatureSensor parses a temperature sensor name, e.g. "GPU"
+func ParseTemperatureSensor(s string) (TemperatureSensor, error) {
+	return parseEnum("temperature sensor", s, temperatureSensors)
+}
+
+var inforomObjects = []InforomObject{InforomOEM, InforomECC, InforomPower}
+
+// ParseInforomObject parses an inforom object name, e.g. "ECC"
+func ParseInforomObject(s string) (InforomObject, error) {
+	return parseEnum("inforom object", s, inforomObjects)
+}
+
+var clockTypes = []ClockType{ClockGraphics, ClockSM, ClockMem, ClockVideo}
+
+// ParseClockType parses a clock domain name, e.g. "SM"
+func ParseClockType(s string) (ClockType, error) {
+	return parseEnum("clock type", s, clockTypes)
+}
+
+// ParseClocksThrottleReason parses a set of throttle reasons, e.g.
+// "SwPowerCap|HwSlowdown" or "None"
+func ParseClocksThrottleReason(s string) (ClocksThrottleReason, error) {
+	return parseMask("clocks throttle reason", s, append([]ClocksThrottleReason{ClocksThrottleReasonNone}, ClocksThrottleReasons...))
+}
+
+var computeModes = []ComputeMode{
+	ComputeModeDefault, ComputeModeExclusiveThread, ComputeModeProhibited, ComputeModeExclusiveProcess,
+}
+
+// ParseComputeMode parses a compute mode name, e.g. "ExclusiveProcess"
+func ParseComputeMode(s string) (ComputeMode, error) {
+	return parseEnum("compute mode", s, computeModes)
+}
+
+var gpuOperationModes = []GpuOperationMode{GomAllOn, GomCompute, GomLowDP}
+
+// ParseGpuOperationMode parses a GPU operation mode name, e.g. "Compute"
+func ParseGpuOperationMode(s string) (GpuOperationMode, error) {
+	return parseEnum("GPU operation mode", s, gpuOperationModes)
+}
+
+var restrictedAPIs = []RestrictedAPI{RestrictedAPISetApplicationClocks, RestrictedAPISetAutoBoostedClocks}
+
+// ParseRestrictedAPI parses a restricted API name, e.g. "SetApplicationClocks"
+func ParseRestrictedAPI(s string) (RestrictedAPI, error) {
+	return parseEnum("restricted API", s, restrictedAPIs)
+}
+
+var bridgeChipTypes = []BridgeChipType{BridgeChipPLX, BridgeChipBRO4}
+
+// ParseBridgeChipType parses a bridge chip type name, e.g. "PLX"
+func ParseBridgeChipType(s string) (BridgeChipType, error) {
+	return parseEnum("bridge chip type", s, bridgeChipTypes)
+}
+
+var memoryErrorTypes = []MemoryErrorType{MemoryErrorCorrected, MemoryErrorUncorrected}
+
+// ParseMemoryErrorType parses a memory error type name, e.g. "Uncorrected"
+func ParseMemoryErrorType(s string) (MemoryErrorType, error) {
+	return parseEnum("memory error type", s, memoryErrorTypes)
+}
+
+var eccCounterTypes = []EccCounterType{EccCounterVolatile, EccCounterAggregate}
+
+// ParseEccCounterType parses an ECC counter type name, e.g. "Volatile"
+func ParseEccCounterType(s string) (EccCounterType, error) {
+	return parseEnum("ECC counter type", s, eccCounterTypes)
+}
+
+var memoryLocations = []MemoryLocation{
+	MemoryLocationL1Cache, MemoryLocationL2Cache, MemoryLocationDeviceMemory,
+	MemoryLocationRegisterFile, MemoryLocationTextureMemory, MemoryLocationTextureShm,
+}
+
+// ParseMemoryLocation parses a memory location name, e.g. "DeviceMemory"
+func ParseMemoryLocation(s string) (MemoryLocation, error) {
+	return parseEnum("memory location", s, memoryLocations)
+}
+
+var pageRetirementCauses = []PageRetirementCause{
+	PageRetirementMultipleSingleBitEccErrors, PageRetirementDoubleBitEccError,
+}
+
+// ParsePageRetirementCause parses a page retirement cause name, e.g.
+// "DoubleBitEccError"
+func ParsePageRetirementCause(s string) (PageRetirementCause, error) {
+	return parseEnum("page retirement cause", s, pageRetirementCauses)
+}
+
+var eventTypes = []EventTypeMask{
+	EventTypeSingleBitEccError, EventTypeDoubleBitEccError, EventTypePState,
+	EventTypeXidCriticalError, EventTypeClock,
+}
+
+// ParseEventTypeMask parses a set of event types, e.g. "PState|Clock", "None"
+// or "All"
+func ParseEventTypeMask(s string) (EventTypeMask, error) {
+	return parseMask("event type", s, append([]EventTypeMask{EventTypeNone, EventTypeAll}, eventTypes...))
+}
+
+var nvLinkCapabilities = []NvLinkCapability{
+	NvLinkCapP2PSupported, NvLinkCapSysmemAccess, NvLinkCapP2PAtomics,
+	NvLinkCapSysmemAtomics, NvLinkCapSLIBridge, NvLinkCapValid,
+}
+
+// ParseNvLinkCapability parses an NVLink capability name, e.g. "P2PAtomics"
+func ParseNvLinkCapability(s string) (NvLinkCapability, error) {
+	return parseEnum("NVLink capability", s, nvLinkCapabilities)
+}
+
+var nvLinkCounterUnits = []NvLinkCounterUnit{NvLinkCounterUnitCycles, NvLinkCounterUnitPackets, NvLinkCounterUnitBytes}
+
+// ParseNvLinkCounterUnit parses an NVLink counter unit name, e.g. "Bytes"
+func ParseNvLinkCounterUnit(s string) (NvLinkCounterUnit, error) {
+	return parseEnum("NVLink counter unit", s, nvLinkCounterUnits)
+}
+
+var nvLinkPacketTypes = []NvLinkPacketType{
+	NvLinkPacketNop, NvLinkPacketRead, NvLinkPacketWrite, NvLinkPacketRatom,
+	NvLinkPacketNratom, NvLinkPacketFlush, NvLinkPacketRespData, NvLinkPacketRespNoData,
+}
+
+// ParseNvLinkPacketType parses a set of NVLink packet types, e.g.
+// "Read|Write" or "All"
+func ParseNvLinkPacketType(s string) (NvLinkPacketType, error) {
+	return parseMask("NVLink packet type", s, append([]NvLinkPacketType{NvLinkPacketAll}, nvLinkPacketTypes...))
+}
+
+var samplingTypes = []SamplingType{
+	SamplesTotalPower, SamplesGPUUtilization, SamplesMemoryUtilization, SamplesEncoderUtilization,
+	SamplesDecoderUtilization, SamplesProcessorClock, SamplesMemoryClock,
+}
+
+// ParseSamplingType parses a sampling type name, e.g. "GPUUtilization"
+func ParseSamplingType(s string) (SamplingType, error) {
+	return parseEnum("sampling type", s, samplingTypes)
+}
+
+var topologyLevels = []TopologyLevel{
+	TopologyInternal, TopologySingle, TopologyMultiple, TopologyHostBridge, TopologyCPU, TopologySystem,
+}
+
+// ParseTopologyLevel parses a topology level name, e.g. "HostBridge"
+func ParseTopologyLevel(s string) (TopologyLevel, error) {
+	return parseEnum("topology level", s, topologyLevels)
+}
+
+var p2pCapsIndexes = []P2PCapsIndex{P2PCapsRead, P2PCapsWrite, P2PCapsNvLink, P2PCapsAtomics, P2PCapsProp}
+
+// ParseP2PCapsIndex parses a peer-to-peer capability name, e.g. "NvLink"
+func ParseP2PCapsIndex(s string) (P2PCapsIndex, error) {
+	return parseEnum("P2P capability", s, p2pCapsIndexes)
+}
+
+var p2pStatuses = []P2PStatus{
+	P2PStatusOK, P2PStatusChipsetNotSupported, P2PStatusGPUNotSupported, P2PStatusIOHTopologyNotSupported,
+	P2PStatusDisabledByRegkey, P2PStatusNotSupported, P2PStatusUnknown,
+}
+
+// ParseP2PStatus parses a peer-to-peer status name, e.g. "OK"
+func ParseP2PStatus(s string) (P2PStatus, error) {
+	return parseEnum("P2P status", s, p2pStatuses)
+}
+
+var unitTemperatureTypes = []UnitTemperatureType{UnitTemperatureIntake, UnitTemperatureExhaust, UnitTemperatureBoard}
+
+// ParseUnitTemperatureType parses a unit temperature sensor name, e.g.
+// "Intake"
+func ParseUnitTemperatureType(s string) (UnitTemperatureType, error) {
+	return parseEnum("unit temperature type", s, unitTemperatureTypes)
+}
@@ -0,0 +1,98 @@
+package nvml
+
+import (
+	"fmt"
+	"testing"
+)
+
+// roundTrip checks that every value in values parses back from its name
+func roundTrip[T fmt.Stringer](t *testing.T, values []T, parse func(string) (T, error)) {
+	t.Helper()
+
+	seen := make(map[string]bool)
+	for _, v := range values {
+		name := v.String()
+		if seen[name] {
+			t.Errorf("duplicate name %q", name)
+		}
+		seen[name] = true
+
+		parsed, err := parse(name)
+		if err != nil {
+			t.Errorf("parsing %q: %v", name, err)
+			continue
+		}
+		if parsed.String() != name {
+			t.Errorf("parsing %q returned %v", name, parsed)
+		}
+	}
+
+	if _, err := parse("bogus"); err == nil {
+		t.Errorf("parsing \"bogus\" succeeded")
+	}
+}
+
+func TestEnumRoundTrip(t *testing.T) {
+	roundTrip(t, pstates, ParsePstate)
+	roundTrip(t, returns, ParseReturn)
+	roundTrip(t, brands, ParseBrand)
+	roundTrip(t, temperatureSensors, ParseTemperatureSensor)
+	roundTrip(t, inforomObjects, ParseInforomObject)
+	roundTrip(t, clockTypes, ParseClockType)
+	roundTrip(t, ClocksThrottleReasons, ParseClocksThrottleReason)
+	roundTrip(t, computeModes, ParseComputeMode)
+	roundTrip(t, gpuOperationModes, ParseGpuOperationMode)
+	roundTrip(t, restrictedAPIs, ParseRestrictedAPI)
+	roundTrip(t, bridgeChipTypes, ParseBridgeChipType)
+	roundTrip(t, memoryErrorTypes, ParseMemoryErrorType)
+	roundTrip(t, eccCounterTypes, ParseEccCounterType)
+	roundTrip(t, memoryLocations, ParseMemoryLocation)
+	roundTrip(t, pageRetirementCauses, ParsePageRetirementCause)
+	roundTrip(t, eventTypes, ParseEventTypeMask)
+	roundTrip(t, nvLinkCapabilities, ParseNvLinkCapability)
+	roundTrip(t, nvLinkCounterUnits, ParseNvLinkCounterUnit)
+	roundTrip(t, nvLinkPacketTypes, ParseNvLinkPacketType)
+	roundTrip(t, samplingTypes, ParseSamplingType)
+	roundTrip(t, topologyLevels, ParseTopologyLevel)
+	roundTrip(t, p2pCapsIndexes, ParseP2PCapsIndex)
+	roundTrip(t, p2pStatuses, ParseP2PStatus)
+	roundTrip(t, unitTemperatureTypes, ParseUnitTemperatureType)
+}
+
+func TestClocksThrottleReasonMask(t *testing.T) {
+	var tests = []struct {
+		reason ClocksThrottleReason
+		name   string
+	}{
+		{ClocksThrottleReasonNone, "None"},
+		{ClocksThrottleReasonSwPowerCap, "SwPowerCap"},
+		{ClocksThrottleReasonSwPowerCap | ClocksThrottleReasonHwSlowdown, "SwPowerCap|HwSlowdown"},
+		{ClocksThrottleReasonGpuIdle | 0x100, "GpuIdle|0x100"},
+	}
+
+	for _, test := range tests {
+		if name := test.reason.String(); name != test.name {
+			t.Errorf("%#x.String() = %q, want %q", uint64(test.reason), name, test.name)
+		}
+
+		reason, err := ParseClocksThrottleReason(test.name)
+		if err != nil {
+			t.Errorf("ParseClocksThrottleReason(%q): %v", test.name, err)
+		} else if reason != test.reason {
+			t.Errorf("ParseClocksThrottleReason(%q) = %#x, want %#x", test.name, uint64(reason), uint64(test.reason))
+		}
+	}
+
+	if _, err := ParseClocksThrottleReason("SwPowerCap|bogus"); err == nil {
+		t.Errorf("parsing an unknown reason succeeded")
+	}
+}
+
+func TestParseIgnoresCase(t *testing.T) {
+	if mode, err := ParseComputeMode("exclusiveprocess"); err != nil || mode != ComputeModeExclusiveProcess {
+		t.Errorf("ParseComputeMode(\"exclusiveprocess\") = %v, %v", mode, err)
+	}
+	if mask, err := ParseEventTypeMask("pstate | clock"); err != nil || mask != EventTypePState|EventTypeClock {
+		t.Errorf("ParseEventTypeMask(\"pstate | clock\") = %v, %v", mask, err)
+	}
+}
@@ -0,0 +1,72 @@
+package nvml
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotSupported matches, with errors.Is, NVML errors reporting that the
+// query or operation isn't supported by the device, as opposed to having
+// failed. Exporters can use it to silently skip metrics a SKU doesn't have.
+var ErrNotSupported = errors.New("not supported")
+
+// ErrGPUIsLost matches, with errors.Is, NVML errors reporting that the GPU has
+// fallen off the bus or otherwise become inaccessible
+var ErrGPUIsLost = errors.New("GPU is lost")
+
+// ErrFunctionNotFound matches, with errors.Is, NVML errors reporting that the
+// loaded NVML library is too old to implement a function, or that it was
+// compiled out with the nvml_legacy build tag
+var ErrFunctionNotFound = errors.New("function not found")
+
+// sentinelErrors maps NVML return codes to the sentinel errors they match
+var sentinelErrors = map[int]error{
+	C.NVML_ERROR_NOT_SUPPORTED:      ErrNotSupported,
+	C.NVML_ERROR_GPU_IS_LOST:        ErrGPUIsLost,
+	C.NVML_ERROR_FUNCTION_NOT_FOUND: ErrFunctionNotFound,
+}
+
+// NVMLError is an error returned by an NVML function. Function is the name of
+// the C function that failed, e.g. nvmlDeviceGetTemperature, and Code is the
+// nvmlReturn_t it returned. All wrappers return their NVML failures
+// as *NVMLError, so they can be told apart with errors.Is and sentinel errors
+// such as ErrNotSupported.
+type NVMLError struct {
+	Function string
+	Code     int
+}
+
+func (e *NVMLError) Error() string {
+	cerrorstring := C.nvmlErrorString(C.nvmlReturn_t(e.Code))
+	if cerrorstring == nil {
+		return fmt.Sprintf("%s returned error %d", e.Function, e.Code)
+	}
+
+	return fmt.Sprintf("%s returned error %d: %s", e.Function, e.Code, C.GoString(cerrorstring))
+}
+
+// Return returns the error's code as a Return
+func (e *NVMLError) Return() Return {
+	return Return(e.Code)
+}
+
+// Is makes errors.Is match the sentinel error for the error's code
+func (e *NVMLError) Is(target error) bool {
+	sentinel, ok := sentinelErrors[e.Code]
+	return ok && sentinel == target
+}
+
+// newNVMLError returns an *NVMLError for the given result of function, or nil
+// if it succeeded
+func newNVMLError(function string, result C.nvmlReturn_t) error {
+	if result == C.NVML_SUCCESS {
+		return nil
+	}
+
+	return &NVMLError{Function: function, Code: int(result)}
+}
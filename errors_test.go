@@ -0,0 +1,21 @@
+package nvml
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNVMLErrorIs(t *testing.T) {
+	for code, sentinel := range sentinelErrors {
+		err := fmt.Errorf("wrapped: %w", &NVMLError{Function: "test", Code: code})
+		if !errors.Is(err, sentinel) {
+			t.Errorf("errors.Is(code %d, %v) = false", code, sentinel)
+		}
+
+		other := &NVMLError{Function: "test", Code: code + 1000}
+		if errors.Is(other, sentinel) {
+			t.Errorf("errors.Is(code %d, %v) = true", other.Code, sentinel)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package nvml
+
+// See https://docs.nvidia.com/deploy/nvml-api/group__nvmlEvents.html
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// EventTypeMask is a set of event types, combined with bitwise or
+type EventTypeMask uint64
+
+const (
+	EventTypeSingleBitEccError EventTypeMask = C.nvmlEventTypeSingleBitEccError
+	EventTypeDoubleBitEccError EventTypeMask = C.nvmlEventTypeDoubleBitEccError
+	EventTypePState            EventTypeMask = C.nvmlEventTypePState
+	EventTypeXidCriticalError  EventTypeMask = C.nvmlEventTypeXidCriticalError
+	EventTypeClock             EventTypeMask = C.nvmlEventTypeClock
+	EventTypeNone              EventTypeMask = C.nvmlEventTypeNone
+	EventTypeAll               EventTypeMask = C.nvmlEventTypeAll
+)
+
+func (m EventTypeMask) String() string {
+	switch m {
+	case EventTypeSingleBitEccError:
+		return "SingleBitEccError"
+	case EventTypeDoubleBitEccError:
+		return "DoubleBitEccError"
+	case EventTypePState:
+		return "PState"
+	case EventTypeXidCriticalError:
+		return "XidCriticalError"
+	case EventTypeClock:
+		return "Clock"
+	case EventTypeNone:
+		return "None"
+	case EventTypeAll:
+		return "All"
+	}
+
+	return maskString(m, eventTypes)
+}
+
+// ErrTimeout is returned by EventSet.Wait when no event arrived in time
+var ErrTimeout = errors.New("timed out waiting for event")
+
+// Event is an event that occurred on a device. For EventTypeXidCriticalError
+// events, Data holds the XID of the error (999 for an unknown XID); it is 0
+// for all other event types.
+type Event struct {
+	Device    *Device
+	EventType EventTypeMask
+	Data      uint64
+}
+
+// EventSet is a set of devices and event types to wait for. It must be freed
+// with Free once it is no longer needed.
+type EventSet struct {
+	set C.nvmlEventSet_t
+
+	mu      sync.Mutex
+	devices []registeredDevice
+}
+
+// registeredDevice is a device added to an EventSet, and its handle at the
+// time
+type registeredDevice struct {
+	gpu    *Device
+	handle C.nvmlDevice_t
+}
+
+// device returns the registered device an event with the given handle
+// occurred on. Handles are compared to the devices' current handles first, so
+// devices stay recognized after Device.Refresh replaced their handle.
+func (set *EventSet) device(handle C.nvmlDevice_t) *Device {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	for _, registered := range set.devices {
+		if registered.gpu.handle() == handle {
+			return registered.gpu
+		}
+	}
+	for _, registered := range set.devices {
+		if registered.handle == handle {
+			return registered.gpu
+		}
+	}
+
+	return nil
+}
+
+// NewEventSet creates an empty event set
+func NewEventSet() (*EventSet, error) {
+	var result C.nvmlReturn_t
+	var cset C.nvmlEventSet_t
+
+	if err := requireFunction("nvmlEventSetCreate"); err != nil {
+		return nil, err
+	}
+
+	result = C.nvmlEventSetCreate(&cset)
+	if result != C.NVML_SUCCESS {
+		return nil, newNVMLError("nvmlEventSetCreate", result)
+	}
+
+	return &EventSet{set: cset}, nil
+}
+
+// SupportedEventTypes returns the event types the device supports
+func (gpu *Device) SupportedEventTypes() (EventTypeMask, error) {
+	var result C.nvmlReturn_t
+	var ctypes C.ulonglong
+
+	if err := requireFunction("nvmlDeviceGetSupportedEventTypes"); err != nil {
+		return EventTypeNone, err
+	}
+
+	result = C.nvmlDeviceGetSupportedEventTypes(gpu.handle(), &ctypes)
+	if result != C.NVML_SUCCESS {
+		return EventTypeNone, newNVMLError("nvmlDeviceGetSupportedEventTypes", result)
+	}
+
+	return EventTypeMask(ctypes), nil
+}
+
+// RegisterEvents adds the given event types of the device to the event set.
+// Registering unsupported event types returns an error; use
+// SupportedEventTypes to find out which ones the device supports.
+func (gpu *Device) RegisterEvents(eventTypes EventTypeMask, set *EventSet) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceRegisterEvents"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceRegisterEvents(gpu.handle(), C.ulonglong(eventTypes), set.set)
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceRegisterEvents", result)
+	}
+
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	for i := range set.devices {
+		if set.devices[i].gpu == gpu {
+			set.devices[i].handle = gpu.handle()
+			return nil
+		}
+	}
+	set.devices = append(set.devices, registeredDevice{gpu: gpu, handle: gpu.handle()})
+
+	return nil
+}
+
+// Wait waits up to timeoutMs milliseconds for an event to occur on any of the
+// registered devices, and returns ErrTimeout if none did. Events that arrive
+// while nobody is waiting are queued.
+func (set *EventSet) Wait(timeoutMs uint) (Event, error) {
+	var result C.nvmlReturn_t
+	var cdata C.nvmlEventData_t
+	var event Event
+
+	if err := requireFunction("nvmlEventSetWait"); err != nil {
+		return event, err
+	}
+
+	result = C.nvmlEventSetWait(set.set, &cdata, C.uint(timeoutMs))
+	if result == C.NVML_ERROR_TIMEOUT {
+		return event, ErrTimeout
+	}
+	if result != C.NVML_SUCCESS {
+		return event, newNVMLError("nvmlEventSetWait", result)
+	}
+
+	event.Device = set.device(cdata.device)
+	event.EventType = EventTypeMask(cdata.eventType)
+	event.Data = uint64(cdata.eventData)
+
+	return event, nil
+}
+
+// eventPollIntervalMs is how long each NVML wait in WaitContext lasts, and so
+// bounds how long it takes to notice that the context was cancelled.
+const eventPollIntervalMs = 100
+
+// WaitContext waits for an event to occur on any of the registered devices
+// until the context is cancelled, in which case it returns the context's
+// error. Unlike Wait it doesn't block in NVML for long, so callers can shut
+// down promptly.
+func (set *EventSet) WaitContext(ctx context.Context) (Event, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return Event{}, ctx.Err()
+		default:
+		}
+
+		event, err := set.Wait(eventPollIntervalMs)
+		if err != ErrTimeout {
+			return event, err
+		}
+	}
+}
+
+// Free releases the event set. It must not be used afterwards.
+func (set *EventSet) Free() error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlEventSetFree"); err != nil {
+		return err
+	}
+
+	result = C.nvmlEventSetFree(set.set)
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlEventSetFree", result)
+	}
+
+	return nil
+}
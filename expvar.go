@@ -0,0 +1,76 @@
+package nvml
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PublishExpvars publishes the snapshots of the given devices as the expvar
+// prefix, keyed by device index, and refreshes them every interval until the
+// returned function is called. A device whose snapshot fails is removed until
+// it can be read again, so stale readings of a lost GPU aren't reported. Like
+// expvar.Publish, it panics if prefix is already in use.
+func PublishExpvars(prefix string, devices []*Device, interval time.Duration) (stop func()) {
+	var mu sync.Mutex
+	snapshots := make(map[string]DeviceSnapshot)
+	keys := make([]string, len(devices)) // Key each device was last published as
+
+	update := func() {
+		for i, gpu := range devices {
+			snapshot, err := gpu.Snapshot()
+			if err != nil {
+				mu.Lock()
+				delete(snapshots, keys[i])
+				mu.Unlock()
+				continue
+			}
+
+			key := strconv.Itoa(i)
+			if index, err := gpu.Index(); err == nil {
+				key = strconv.FormatUint(uint64(index), 10)
+			}
+
+			mu.Lock()
+			if keys[i] != "" && keys[i] != key {
+				delete(snapshots, keys[i])
+			}
+			keys[i] = key
+			snapshots[key] = snapshot
+			mu.Unlock()
+		}
+	}
+
+	update()
+	expvar.Publish(prefix, expvar.Func(func() interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+
+		out := make(map[string]DeviceSnapshot, len(snapshots))
+		for k, v := range snapshots {
+			out[k] = v
+		}
+		return out
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				update()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
@@ -0,0 +1,149 @@
+// Code generated by nvmlgen from nvml.h; DO NOT EDIT.
+
+package nvml
+
+// headerFunctions are the symbols of the functions declared in nvml.h
+var headerFunctions = []string{
+	"nvmlInit_v2",
+	"nvmlShutdown",
+	"nvmlSystemGetDriverVersion",
+	"nvmlSystemGetNVMLVersion",
+	"nvmlSystemGetProcessName",
+	"nvmlUnitGetCount",
+	"nvmlUnitGetHandleByIndex",
+	"nvmlUnitGetUnitInfo",
+	"nvmlUnitGetLedState",
+	"nvmlUnitGetPsuInfo",
+	"nvmlUnitGetTemperature",
+	"nvmlUnitGetFanSpeedInfo",
+	"nvmlUnitGetDevices",
+	"nvmlSystemGetHicVersion",
+	"nvmlDeviceGetCount_v2",
+	"nvmlDeviceGetHandleByIndex_v2",
+	"nvmlDeviceGetHandleBySerial",
+	"nvmlDeviceGetHandleByUUID",
+	"nvmlDeviceGetHandleByPciBusId_v2",
+	"nvmlDeviceGetName",
+	"nvmlDeviceGetBrand",
+	"nvmlDeviceGetIndex",
+	"nvmlDeviceGetSerial",
+	"nvmlDeviceGetCpuAffinity",
+	"nvmlDeviceSetCpuAffinity",
+	"nvmlDeviceClearCpuAffinity",
+	"nvmlDeviceGetTopologyCommonAncestor",
+	"nvmlDeviceGetTopologyNearestGpus",
+	"nvmlSystemGetTopologyGpuSet",
+	"nvmlDeviceGetP2PStatus",
+	"nvmlDeviceGetUUID",
+	"nvmlDeviceGetMinorNumber",
+	"nvmlDeviceGetBoardPartNumber",
+	"nvmlDeviceGetInforomVersion",
+	"nvmlDeviceGetInforomImageVersion",
+	"nvmlDeviceGetInforomConfigurationChecksum",
+	"nvmlDeviceValidateInforom",
+	"nvmlDeviceGetDisplayMode",
+	"nvmlDeviceGetDisplayActive",
+	"nvmlDeviceGetPersistenceMode",
+	"nvmlDeviceGetPciInfo_v2",
+	"nvmlDeviceGetMaxPcieLinkGeneration",
+	"nvmlDeviceGetMaxPcieLinkWidth",
+	"nvmlDeviceGetCurrPcieLinkGeneration",
+	"nvmlDeviceGetCurrPcieLinkWidth",
+	"nvmlDeviceGetPcieThroughput",
+	"nvmlDeviceGetPcieReplayCounter",
+	"nvmlDeviceGetClockInfo",
+	"nvmlDeviceGetMaxClockInfo",
+	"nvmlDeviceGetApplicationsClock",
+	"nvmlDeviceGetDefaultApplicationsClock",
+	"nvmlDeviceResetApplicationsClocks",
+	"nvmlDeviceGetClock",
+	"nvmlDeviceGetMaxCustomerBoostClock",
+	"nvmlDeviceGetSupportedMemoryClocks",
+	"nvmlDeviceGetSupportedGraphicsClocks",
+	"nvmlDeviceGetAutoBoostedClocksEnabled",
+	"nvmlDeviceSetAutoBoostedClocksEnabled",
+	"nvmlDeviceSetDefaultAutoBoostedClocksEnabled",
+	"nvmlDeviceGetFanSpeed",
+	"nvmlDeviceGetTemperature",
+	"nvmlDeviceGetTemperatureThreshold",
+	"nvmlDeviceGetPerformanceState",
+	"nvmlDeviceGetCurrentClocksThrottleReasons",
+	"nvmlDeviceGetSupportedClocksThrottleReasons",
+	"nvmlDeviceGetPowerState",
+	"nvmlDeviceGetPowerManagementMode",
+	"nvmlDeviceGetPowerManagementLimit",
+	"nvmlDeviceGetPowerManagementLimitConstraints",
+	"nvmlDeviceGetPowerManagementDefaultLimit",
+	"nvmlDeviceGetPowerUsage",
+	"nvmlDeviceGetEnforcedPowerLimit",
+	"nvmlDeviceGetGpuOperationMode",
+	"nvmlDeviceGetMemoryInfo",
+	"nvmlDeviceGetComputeMode",
+	"nvmlDeviceGetEccMode",
+	"nvmlDeviceGetBoardId",
+	"nvmlDeviceGetMultiGpuBoard",
+	"nvmlDeviceGetTotalEccErrors",
+	"nvmlDeviceGetDetailedEccErrors",
+	"nvmlDeviceGetMemoryErrorCounter",
+	"nvmlDeviceGetUtilizationRates",
+	"nvmlDeviceGetEncoderUtilization",
+	"nvmlDeviceGetDecoderUtilization",
+	"nvmlDeviceGetDriverModel",
+	"nvmlDeviceGetVbiosVersion",
+	"nvmlDeviceGetBridgeChipInfo",
+	"nvmlDeviceGetComputeRunningProcesses",
+	"nvmlDeviceGetGraphicsRunningProcesses",
+	"nvmlDeviceOnSameBoard",
+	"nvmlDeviceGetAPIRestriction",
+	"nvmlDeviceGetSamples",
+	"nvmlDeviceGetBAR1MemoryInfo",
+	"nvmlDeviceGetViolationStatus",
+	"nvmlDeviceGetAccountingMode",
+	"nvmlDeviceGetAccountingStats",
+	"nvmlDeviceGetAccountingPids",
+	"nvmlDeviceGetAccountingBufferSize",
+	"nvmlDeviceGetRetiredPages",
+	"nvmlDeviceGetRetiredPagesPendingStatus",
+	"nvmlUnitSetLedState",
+	"nvmlDeviceSetPersistenceMode",
+	"nvmlDeviceSetComputeMode",
+	"nvmlDeviceSetEccMode",
+	"nvmlDeviceClearEccErrorCounts",
+	"nvmlDeviceSetDriverModel",
+	"nvmlDeviceSetApplicationsClocks",
+	"nvmlDeviceSetPowerManagementLimit",
+	"nvmlDeviceSetGpuOperationMode",
+	"nvmlDeviceSetAPIRestriction",
+	"nvmlDeviceSetAccountingMode",
+	"nvmlDeviceClearAccountingPids",
+	"nvmlDeviceGetNvLinkState",
+	"nvmlDeviceGetNvLinkVersion",
+	"nvmlDeviceGetNvLinkCapability",
+	"nvmlDeviceGetNvLinkRemotePciInfo",
+	"nvmlDeviceGetNvLinkErrorCounter",
+	"nvmlDeviceResetNvLinkErrorCounters",
+	"nvmlDeviceSetNvLinkUtilizationControl",
+	"nvmlDeviceGetNvLinkUtilizationControl",
+	"nvmlDeviceGetNvLinkUtilizationCounter",
+	"nvmlDeviceFreezeNvLinkUtilizationCounter",
+	"nvmlDeviceResetNvLinkUtilizationCounter",
+	"nvmlEventSetCreate",
+	"nvmlDeviceRegisterEvents",
+	"nvmlDeviceGetSupportedEventTypes",
+	"nvmlEventSetWait",
+	"nvmlEventSetFree",
+	"nvmlDeviceModifyDrainState",
+	"nvmlDeviceQueryDrainState",
+	"nvmlDeviceRemoveGpu",
+	"nvmlDeviceDiscoverGpus",
+}
+
+// functionSymbols maps the functions nvml.h renames to versioned symbols to
+// those symbols
+var functionSymbols = map[string]string{
+	"nvmlInit":                      "nvmlInit_v2",
+	"nvmlDeviceGetCount":            "nvmlDeviceGetCount_v2",
+	"nvmlDeviceGetHandleByIndex":    "nvmlDeviceGetHandleByIndex_v2",
+	"nvmlDeviceGetHandleByPciBusId": "nvmlDeviceGetHandleByPciBusId_v2",
+	"nvmlDeviceGetPciInfo":          "nvmlDeviceGetPciInfo_v2",
+}
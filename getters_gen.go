@@ -0,0 +1,119 @@
+// Code generated by nvmlgen from nvml.h; DO NOT EDIT.
+
+package nvml
+
+// Name returns the name of this device
+func (gpu *Device) Name() (string, error) {
+	return gpu.textProperty("Name")
+}
+
+// Index returns the NVML index of this device
+func (gpu *Device) Index() (uint, error) {
+	return gpu.intProperty("Index")
+}
+
+// Serial returns the globally unique board serial number associated with this
+// device's board
+func (gpu *Device) Serial() (string, error) {
+	return gpu.textProperty("Serial")
+}
+
+// UUID returns the globally unique immutable UUID associated with this device,
+// as a 5 part hexadecimal string, that augments the immutable, board serial
+// identifier
+func (gpu *Device) UUID() (string, error) {
+	return gpu.textProperty("UUID")
+}
+
+// MinorNumber returns minor number for the device
+func (gpu *Device) MinorNumber() (uint, error) {
+	return gpu.intProperty("MinorNumber")
+}
+
+// BoardPartNumber returns the the device board part number which is programmed
+// into the board's InfoROM
+func (gpu *Device) BoardPartNumber() (string, error) {
+	return gpu.textProperty("BoardPartNumber")
+}
+
+// InforomImageVersion returns the global infoROM image version
+func (gpu *Device) InforomImageVersion() (string, error) {
+	return gpu.textProperty("InforomImageVersion")
+}
+
+// InforomConfigurationChecksum returns the checksum of the configuration stored
+// in the device's infoROM
+func (gpu *Device) InforomConfigurationChecksum() (uint, error) {
+	return gpu.intProperty("InforomConfigurationChecksum")
+}
+
+// MaxPCIeLinkGeneration returns the maximum PCIe link generation possible with
+// this device and system
+func (gpu *Device) MaxPCIeLinkGeneration() (uint, error) {
+	return gpu.intProperty("MaxPcieLinkGeneration")
+}
+
+// MaxPCIeLinkWidth returns the maximum PCIe link width possible with this
+// device and system
+func (gpu *Device) MaxPCIeLinkWidth() (uint, error) {
+	return gpu.intProperty("MaxPcieLinkWidth")
+}
+
+// CurrPCIeLinkGeneration returns the current PCIe link generation
+func (gpu *Device) CurrPCIeLinkGeneration() (uint, error) {
+	return gpu.intProperty("CurrPcieLinkGeneration")
+}
+
+// CurrPCIeLinkWidth returns the current PCIe link width
+func (gpu *Device) CurrPCIeLinkWidth() (uint, error) {
+	return gpu.intProperty("CurrPcieLinkWidth")
+}
+
+// PCIeReplayCounter returns the PCIe replay counter
+func (gpu *Device) PCIeReplayCounter() (uint, error) {
+	return gpu.intProperty("PcieReplayCounter")
+}
+
+// FanSpeed returns the intended operating speed of the device's fan
+func (gpu *Device) FanSpeed() (uint, error) {
+	return gpu.intProperty("FanSpeed")
+}
+
+// PowerManagementLimit returns the power management limit of the device, in mW
+func (gpu *Device) PowerManagementLimit() (uint, error) {
+	return gpu.intProperty("PowerManagementLimit")
+}
+
+// PowerManagementDefaultLimit returns default power management limit on this
+// device, in milliwatts
+func (gpu *Device) PowerManagementDefaultLimit() (uint, error) {
+	return gpu.intProperty("PowerManagementDefaultLimit")
+}
+
+// PowerUsage returns power usage for this GPU in milliwatts and its associated
+// circuitry (e.g. memory)
+func (gpu *Device) PowerUsage() (uint, error) {
+	return gpu.intProperty("PowerUsage")
+}
+
+// EnforcedPowerLimit returns the power limit the driver enforces after taking
+// all limiters into account, in mW
+func (gpu *Device) EnforcedPowerLimit() (uint, error) {
+	return gpu.intProperty("EnforcedPowerLimit")
+}
+
+// BoardId returns the device boardId from 0-N
+func (gpu *Device) BoardId() (uint, error) {
+	return gpu.intProperty("BoardId")
+}
+
+// VbiosVersion returns VBIOS version of the device
+func (gpu *Device) VbiosVersion() (string, error) {
+	return gpu.textProperty("VbiosVersion")
+}
+
+// AccountingBufferSize returns the number of processes that the circular buffer
+// with accounting pids can hold
+func (gpu *Device) AccountingBufferSize() (uint, error) {
+	return gpu.intProperty("AccountingBufferSize")
+}
@@ -0,0 +1,66 @@
+package nvml
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+// DeviceState is the state of a device as determined by State
+type DeviceState int
+
+const (
+	// DeviceStateUnknown devices failed the probe for another reason, e.g.
+	// because NVML isn't initialized
+	DeviceStateUnknown DeviceState = iota
+	// DeviceStateOK devices respond to queries
+	DeviceStateOK
+	// DeviceStateLost devices have fallen off the bus or otherwise become
+	// inaccessible, and need a reset or a reboot
+	DeviceStateLost
+)
+
+func (s DeviceState) String() string {
+	switch s {
+	case DeviceStateUnknown:
+		return "Unknown"
+	case DeviceStateOK:
+		return "OK"
+	case DeviceStateLost:
+		return "Lost"
+	}
+
+	return fmt.Sprintf("DeviceState(%d)", int(s))
+}
+
+// State probes the device with a cheap query that needs to reach the GPU and
+// classifies it by the result. The error is that of the probe; errors.Is(err,
+// ErrGPUIsLost) holds for lost devices.
+func (gpu *Device) State() (DeviceState, error) {
+	var result C.nvmlReturn_t
+	var cpstate C.nvmlPstates_t
+
+	if err := requireFunction("nvmlDeviceGetPerformanceState"); err != nil {
+		return DeviceStateUnknown, err
+	}
+
+	result = C.nvmlDeviceGetPerformanceState(gpu.handle(), &cpstate)
+	switch result {
+	case C.NVML_SUCCESS, C.NVML_ERROR_NOT_SUPPORTED:
+		// Either way, the device answered
+		return DeviceStateOK, nil
+	case C.NVML_ERROR_GPU_IS_LOST:
+		return DeviceStateLost, newNVMLError("nvmlDeviceGetPerformanceState", result)
+	}
+
+	return DeviceStateUnknown, newNVMLError("nvmlDeviceGetPerformanceState", result)
+}
+
+// IsHealthy reports whether the device's State is DeviceStateOK
+func (gpu *Device) IsHealthy() bool {
+	state, _ := gpu.State()
+	return state == DeviceStateOK
+}
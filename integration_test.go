@@ -0,0 +1,180 @@
+//go:build integration
+// +build integration
+
+package nvml
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The integration tests talk to the NVML library and so need a machine with an
+// NVIDIA driver and at least one GPU. Run them with `make integration`, or
+// with `make integration-fake` against the fake library in testdata/fakenvml.
+//
+// For every device, each query below is run and the shape of its outcome is
+// compared against a golden file for the driver's major version and the
+// device's SKU, e.g. testdata/golden/535/tesla-t4.golden. Values such as
+// temperatures change from run to run and differ between SKUs, so a
+// successful query records only the Go type of its result, and a failed one
+// the NVML return code. The golden file also records which of the probed
+// drain functions the driver implements. Devices of the same SKU must agree,
+// so each file holds a single set of outcomes. Regenerate the golden files on
+// real hardware with `make integration-update`.
+
+var update = flag.Bool("update", false, "update golden files")
+
+var integrationQueries = []struct {
+	name string
+	f    func(gpu *Device) (interface{}, error)
+}{
+	{"Index", func(gpu *Device) (interface{}, error) { return gpu.Index() }},
+	{"MinorNumber", func(gpu *Device) (interface{}, error) { return gpu.MinorNumber() }},
+	{"UUID", func(gpu *Device) (interface{}, error) { return gpu.UUID() }},
+	{"Serial", func(gpu *Device) (interface{}, error) { return gpu.Serial() }},
+	{"VbiosVersion", func(gpu *Device) (interface{}, error) { return gpu.VbiosVersion() }},
+	{"InforomImageVersion", func(gpu *Device) (interface{}, error) { return gpu.InforomImageVersion() }},
+	{"InforomConfigurationChecksum", func(gpu *Device) (interface{}, error) { return gpu.InforomConfigurationChecksum() }},
+	{"PerformanceState", func(gpu *Device) (interface{}, error) { return gpu.PerformanceState() }},
+	{"Temp", func(gpu *Device) (interface{}, error) { return gpu.Temp() }},
+	{"FanSpeed", func(gpu *Device) (interface{}, error) { return gpu.FanSpeed() }},
+	{"MaxPCIeLinkGeneration", func(gpu *Device) (interface{}, error) { return gpu.MaxPCIeLinkGeneration() }},
+	{"MaxPCIeLinkWidth", func(gpu *Device) (interface{}, error) { return gpu.MaxPCIeLinkWidth() }},
+	{"CurrPCIeLinkGeneration", func(gpu *Device) (interface{}, error) { return gpu.CurrPCIeLinkGeneration() }},
+	{"CurrPCIeLinkWidth", func(gpu *Device) (interface{}, error) { return gpu.CurrPCIeLinkWidth() }},
+	{"PCIeReplayCounter", func(gpu *Device) (interface{}, error) { return gpu.PCIeReplayCounter() }},
+	{"PowerManagementLimit", func(gpu *Device) (interface{}, error) { return gpu.PowerManagementLimit() }},
+	{"PowerManagementDefaultLimit", func(gpu *Device) (interface{}, error) { return gpu.PowerManagementDefaultLimit() }},
+	{"PowerUsage", func(gpu *Device) (interface{}, error) { return gpu.PowerUsage() }},
+	{"EnforcedPowerLimit", func(gpu *Device) (interface{}, error) { return gpu.EnforcedPowerLimit() }},
+	{"BoardId", func(gpu *Device) (interface{}, error) { return gpu.BoardId() }},
+	{"MultiGpuBoard", func(gpu *Device) (interface{}, error) { return gpu.MultiGpuBoard() }},
+	{"MemoryInfo", func(gpu *Device) (interface{}, error) { return gpu.MemoryInfo() }},
+	{"GetUtilizationRates", func(gpu *Device) (interface{}, error) {
+		u, p, err := gpu.GetUtilizationRates()
+		return [2]uint{u, p}, err
+	}},
+	{"GetEncoderUtilization", func(gpu *Device) (interface{}, error) {
+		u, p, err := gpu.GetEncoderUtilization()
+		return [2]uint{u, p}, err
+	}},
+	{"GetDecoderUtilization", func(gpu *Device) (interface{}, error) {
+		u, p, err := gpu.GetDecoderUtilization()
+		return [2]uint{u, p}, err
+	}},
+}
+
+// outcome describes the result of a query independently of its value
+func outcome(v interface{}, err error) string {
+	var nvmlErr *NVMLError
+	if errors.As(err, &nvmlErr) {
+		return "error " + Return(nvmlErr.Code).String()
+	}
+	if err != nil {
+		return "error"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+func TestIntegrationGolden(t *testing.T) {
+	if err := NVMLInit(); err != nil {
+		t.Fatalf("NVMLInit: %s", err)
+	}
+	defer NVMLShutdown()
+
+	version, err := SystemDriverVersion()
+	if err != nil {
+		t.Fatalf("SystemDriverVersion: %s", err)
+	}
+	major := strings.SplitN(version, ".", 2)[0]
+
+	gpus, err := GetAllGPUs()
+	if err != nil {
+		t.Fatalf("GetAllGPUs: %s", err)
+	}
+
+	var functions bytes.Buffer
+	for _, name := range []string{
+		"nvmlDeviceModifyDrainState", "nvmlDeviceQueryDrainState", "nvmlDeviceRemoveGpu", "nvmlDeviceDiscoverGpus",
+	} {
+		fmt.Fprintf(&functions, "HasFunction(%s): %v\n", name, HasFunction(name))
+	}
+
+	// The outcomes of the first device of each SKU, which the others must
+	// match
+	outcomes := make(map[string][]byte)
+	var skus []string
+	for _, gpu := range gpus {
+		name, err := gpu.Name()
+		if err != nil {
+			t.Fatalf("device %d: Name: %s", gpu.index, err)
+		}
+		sku := skuKey(name)
+
+		out := bytes.NewBuffer(append([]byte(nil), functions.Bytes()...))
+		for _, q := range integrationQueries {
+			fmt.Fprintf(out, "%s: %s\n", q.name, outcome(q.f(gpu)))
+		}
+
+		if expected, ok := outcomes[sku]; ok {
+			if !bytes.Equal(out.Bytes(), expected) {
+				t.Errorf("device %d differs from the other %s devices:\ngot:\n%s\nexpected:\n%s", gpu.index, name, out, expected)
+			}
+			continue
+		}
+		outcomes[sku] = out.Bytes()
+		skus = append(skus, sku)
+	}
+
+	for _, sku := range skus {
+		t.Run(sku, func(t *testing.T) {
+			got := outcomes[sku]
+			golden := filepath.Join("testdata", "golden", major, sku+".golden")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatalf("writing %s: %s", golden, err)
+				}
+				return
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Skipf("no golden file for driver %s (%s); run `make integration-update`", version, golden)
+			}
+
+			if !bytes.Equal(got, expected) {
+				t.Errorf("driver %s output differs from %s:\ngot:\n%s\nexpected:\n%s", version, golden, got, expected)
+			}
+		})
+	}
+}
+
+// skuKey turns a device name into the name of its golden file, e.g.
+// "Tesla T4" into "tesla-t4"
+func skuKey(name string) string {
+	key := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, name)
+
+	for strings.Contains(key, "--") {
+		key = strings.ReplaceAll(key, "--", "-")
+	}
+
+	return strings.Trim(key, "-")
+}
@@ -0,0 +1,413 @@
+// Command nvmlgen generates the parts of package nvml that follow mechanically
+// from nvml.h, so that a new header can be absorbed by running go generate:
+//
+//   - properties_gen.go holds the tables of device getters that intProperty and
+//     textProperty call through the bridge function pointers. Every
+//     nvmlDeviceGet function taking an unsigned int pointer, or a char buffer
+//     whose size the header documents, gets an entry. Entries name the getter's
+//     symbol, which is looked up when first called, so that a getter the driver
+//     lacks doesn't keep the program from loading.
+//   - getters_gen.go holds the typed Device methods wrapping those getters,
+//     named after the function and documented with the first sentence of its
+//     doc comment. Getters whose wrappers convert the value are listed in
+//     customGetters and written by hand.
+//   - return_gen.go holds the Return type, with a constant and a name for each
+//     value of nvmlReturn_t.
+//   - functions_gen.go lists the symbols of all functions the header
+//     declares, which MissingFunctions checks the loaded library for, and maps
+//     the functions the header renames to versioned symbols to those symbols.
+//
+// The rest of the package stays hand-written, and a new header only needs
+// changes there to expose functions of other shapes:
+//
+//   - Functions taking or returning structs, arrays or enums. Their wrappers
+//     convert to Go types whose shape was chosen by hand, e.g. PciInfo or
+//     []ProcessInfo, which the header doesn't describe.
+//   - Enums other than nvmlReturn_t. Their Go constants, e.g. BrandGRID or
+//     GomCompute, were named by hand and are part of the API, so generating
+//     them would rename existing constants.
+//   - Bridge functions. Go calls NVML directly through cgo, so the bridge only
+//     holds the helpers that call through function pointers and batch
+//     queries, which aren't per function.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Function is a function prototype from the header
+type Function struct {
+	Name   string
+	Symbol string   // Name of the versioned symbol, e.g. nvmlInit_v2
+	Params []string // Parameter types, with whitespace normalized
+	Doc    string   // First paragraph of the function's doc comment
+}
+
+// Constant is a member of a C enum
+type Constant struct {
+	Name string
+}
+
+// Header is what nvmlgen extracts from nvml.h
+type Header struct {
+	Functions   []Function
+	Returns     []Constant
+	BufferSizes map[string]string // Function name to buffer size macro
+}
+
+var (
+	prototypeRe   = regexp.MustCompile(`nvmlReturn_t\s+DECLDIR\s+(nvml\w+)\s*\(([^)]*)\)\s*;`)
+	bufferSizeRe  = regexp.MustCompile(`Buffer size guaranteed to be large enough for ([^\n]*)\n\s*\*/\s*#define\s+(NVML_\w+_BUFFER_SIZE)`)
+	refRe         = regexp.MustCompile(`\\ref\s+(nvml\w+)`)
+	versionedRe   = regexp.MustCompile(`(?m)^#define\s+(nvml\w+)\s+(nvml\w+_v\d+)\s*$`)
+	returnEnumRe  = regexp.MustCompile(`(?s)typedef\s+enum\s+nvmlReturn_enum\s*\{(.*?)\}\s*nvmlReturn_t\s*;`)
+	enumMemberRe  = regexp.MustCompile(`(?m)^\s*(NVML_\w+)\s*=`)
+	spaceRe       = regexp.MustCompile(`\s+`)
+	sentenceEndRe = regexp.MustCompile(`\.\s+[A-Z]`)
+)
+
+// paramType strips the name from a parameter declaration, e.g.
+// "unsigned int *speed" becomes "unsigned int*"
+func paramType(param string) string {
+	param = strings.ReplaceAll(param, "*", " * ")
+	fields := strings.Fields(param)
+	if len(fields) > 1 && fields[len(fields)-1] != "*" {
+		fields = fields[:len(fields)-1]
+	}
+
+	return strings.ReplaceAll(strings.Join(fields, " "), " *", "*")
+}
+
+// docComment returns the first paragraph of the doc comment at the end of src,
+// joined into a single line, or "" if src doesn't end in one
+func docComment(src []byte) string {
+	src = bytes.TrimRight(src, " \t\r\n")
+	if !bytes.HasSuffix(src, []byte("*/")) {
+		return ""
+	}
+	start := bytes.LastIndex(src, []byte("/**"))
+	if start < 0 {
+		return ""
+	}
+
+	var words []string
+	for _, line := range strings.Split(string(src[start+3:len(src)-2]), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if line == "" {
+			if len(words) > 0 {
+				break
+			}
+			continue
+		}
+		words = append(words, strings.Fields(line)...)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Parse extracts the prototypes, return codes and buffer sizes from the
+// contents of nvml.h
+func Parse(src []byte) (*Header, error) {
+	h := &Header{BufferSizes: make(map[string]string)}
+
+	symbols := make(map[string]string)
+	for _, m := range versionedRe.FindAllSubmatch(src, -1) {
+		symbols[string(m[1])] = string(m[2])
+	}
+
+	for _, loc := range prototypeRe.FindAllSubmatchIndex(src, -1) {
+		m := [][]byte{nil, src[loc[2]:loc[3]], src[loc[4]:loc[5]]}
+		f := Function{Name: string(m[1]), Symbol: string(m[1]), Doc: docComment(src[:loc[0]])}
+		if symbol, ok := symbols[f.Name]; ok {
+			f.Symbol = symbol
+		}
+		for _, param := range strings.Split(spaceRe.ReplaceAllString(string(m[2]), " "), ",") {
+			f.Params = append(f.Params, paramType(param))
+		}
+		h.Functions = append(h.Functions, f)
+	}
+
+	for _, m := range bufferSizeRe.FindAllSubmatch(src, -1) {
+		for _, ref := range refRe.FindAllSubmatch(m[1], -1) {
+			h.BufferSizes[string(ref[1])] = string(m[2])
+		}
+	}
+
+	m := returnEnumRe.FindSubmatch(src)
+	if m == nil {
+		return nil, fmt.Errorf("nvmlReturn_t not found")
+	}
+	for _, member := range enumMemberRe.FindAllSubmatch(m[1], -1) {
+		h.Returns = append(h.Returns, Constant{Name: string(member[1])})
+	}
+
+	return h, nil
+}
+
+// isIntGetter reports whether f has the signature of a getintProperty
+func (f Function) isIntGetter() bool {
+	return strings.HasPrefix(f.Name, "nvmlDeviceGet") && len(f.Params) == 2 &&
+		f.Params[0] == "nvmlDevice_t" && f.Params[1] == "unsigned int*"
+}
+
+// isTextGetter reports whether f has the signature of a gettextProperty
+func (f Function) isTextGetter() bool {
+	return strings.HasPrefix(f.Name, "nvmlDeviceGet") && len(f.Params) == 3 &&
+		f.Params[0] == "nvmlDevice_t" && f.Params[1] == "char*" && f.Params[2] == "unsigned int"
+}
+
+// CamelCase converts an upper snake case C name to Go, e.g. GPU_IS_LOST to
+// GpuIsLost
+func CamelCase(name string) string {
+	var b strings.Builder
+
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		b.WriteString(word[:1])
+		b.WriteString(strings.ToLower(word[1:]))
+	}
+
+	return b.String()
+}
+
+// returnName is the name of the Return constant for an nvmlReturn_t value,
+// without the Return prefix
+func returnName(name string) string {
+	if trimmed := strings.TrimPrefix(name, "NVML_ERROR_"); trimmed != name {
+		return CamelCase(trimmed)
+	}
+
+	return CamelCase(strings.TrimPrefix(name, "NVML_"))
+}
+
+const header = `// Code generated by nvmlgen from nvml.h; DO NOT EDIT.
+
+package nvml
+`
+
+const preamble = header + `
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+`
+
+// Properties generates properties_gen.go
+func (h *Header) Properties() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString(preamble)
+
+	b.WriteString("\nvar intpropfunctions = map[string]*cIntPropFunc{\n")
+	for _, f := range h.Functions {
+		if f.isIntGetter() {
+			fmt.Fprintf(&b, "\t%q: {symbol: %q},\n", strings.TrimPrefix(f.Name, "nvmlDeviceGet"), f.Symbol)
+		}
+	}
+	b.WriteString("}\n")
+
+	b.WriteString("\nvar textpropfunctions = map[string]*cTextPropFunc{\n")
+	for _, f := range h.Functions {
+		if !f.isTextGetter() {
+			continue
+		}
+		size, ok := h.BufferSizes[f.Name]
+		if !ok {
+			log.Printf("skipping %s: buffer size unknown", f.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "\t%q: {symbol: %q, length: C.%s},\n", strings.TrimPrefix(f.Name, "nvmlDeviceGet"), f.Symbol, size)
+	}
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// customGetters are the table getters whose wrappers convert the value, and
+// so are written by hand
+var customGetters = map[string]bool{
+	"MultiGpuBoard": true,
+}
+
+// getterDocs replaces the header's summary of getters where it leaves out the
+// unit of the value
+var getterDocs = map[string]string{
+	"PowerManagementLimit": "returns the power management limit of the device, in mW",
+	"EnforcedPowerLimit":   "returns the power limit the driver enforces after taking all limiters into account, in mW",
+}
+
+// goName is the name of the Device method wrapping a getter, e.g. FanSpeed
+// for nvmlDeviceGetFanSpeed
+func goName(f Function) string {
+	return strings.ReplaceAll(strings.TrimPrefix(f.Name, "nvmlDeviceGet"), "Pcie", "PCIe")
+}
+
+// summary turns the first sentence of a function's doc comment into the
+// predicate of a Go doc comment, e.g. "Retrieves the name of this device."
+// becomes "returns the name of this device"
+func summary(doc string) string {
+	if loc := sentenceEndRe.FindStringIndex(doc); loc != nil {
+		doc = doc[:loc[0]]
+	}
+	doc = strings.TrimSuffix(doc, ".")
+
+	for _, verb := range []string{"Retrieves ", "Retrieve ", "Returns ", "Gets ", "Get "} {
+		if strings.HasPrefix(doc, verb) {
+			return "returns " + strings.TrimPrefix(doc, verb)
+		}
+	}
+
+	return "calls the NVML function that " + strings.ToLower(doc[:1]) + doc[1:]
+}
+
+// comment formats text as a Go comment wrapped at 80 columns
+func comment(text string) string {
+	var b strings.Builder
+
+	line := "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 80 && line != "//" {
+			b.WriteString(line + "\n")
+			line = "//"
+		}
+		line += " " + word
+	}
+	b.WriteString(line + "\n")
+
+	return b.String()
+}
+
+// Getters generates getters_gen.go, the Device methods wrapping the table
+// getters
+func (h *Header) Getters() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString(header)
+
+	for _, f := range h.Functions {
+		var typ, property string
+		switch {
+		case f.isIntGetter():
+			typ, property = "uint", "intProperty"
+		case f.isTextGetter() && h.BufferSizes[f.Name] != "":
+			typ, property = "string", "textProperty"
+		default:
+			continue
+		}
+
+		key := strings.TrimPrefix(f.Name, "nvmlDeviceGet")
+		if customGetters[key] {
+			continue
+		}
+
+		doc, ok := getterDocs[key]
+		if !ok {
+			doc = summary(f.Doc)
+		}
+
+		b.WriteString("\n")
+		b.WriteString(comment(goName(f) + " " + doc))
+		fmt.Fprintf(&b, "func (gpu *Device) %s() (%s, error) {\n\treturn gpu.%s(%q)\n}\n", goName(f), typ, property, key)
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// ReturnCodes generates return_gen.go
+func (h *Header) ReturnCodes() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString(preamble)
+	b.WriteString("\nimport (\n\t\"fmt\"\n)\n")
+
+	b.WriteString("\n// Return is an nvmlReturn_t, the result of an NVML function\ntype Return int\n\nconst (\n")
+	for _, c := range h.Returns {
+		fmt.Fprintf(&b, "\tReturn%s Return = C.%s\n", returnName(c.Name), c.Name)
+	}
+	b.WriteString(")\n")
+
+	b.WriteString("\nvar returns = []Return{\n")
+	for _, c := range h.Returns {
+		fmt.Fprintf(&b, "\tReturn%s,\n", returnName(c.Name))
+	}
+	b.WriteString("}\n")
+
+	b.WriteString("\nfunc (r Return) String() string {\n\tswitch r {\n")
+	for _, c := range h.Returns {
+		fmt.Fprintf(&b, "\tcase Return%s:\n\t\treturn %q\n", returnName(c.Name), returnName(c.Name))
+	}
+	b.WriteString("\t}\n\n\treturn fmt.Sprintf(\"Return(%d)\", int(r))\n}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// FunctionList generates functions_gen.go
+func (h *Header) FunctionList() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString(header)
+	b.WriteString("\n// headerFunctions are the symbols of the functions declared in nvml.h\nvar headerFunctions = []string{\n")
+	for _, f := range h.Functions {
+		fmt.Fprintf(&b, "\t%q,\n", f.Symbol)
+	}
+	b.WriteString("}\n")
+
+	b.WriteString("\n// functionSymbols maps the functions nvml.h renames to versioned symbols to\n// those symbols\nvar functionSymbols = map[string]string{\n")
+	for _, f := range h.Functions {
+		if f.Symbol != f.Name {
+			fmt.Fprintf(&b, "\t%q: %q,\n", f.Name, f.Symbol)
+		}
+	}
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
+
+func main() {
+	header := flag.String("header", "nvml.h", "path of the NVML header")
+	out := flag.String("out", ".", "directory to write the generated files to")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("nvmlgen: ")
+
+	src, err := os.ReadFile(*header)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	h, err := Parse(src)
+	if err != nil {
+		log.Fatalf("%s: %v", *header, err)
+	}
+
+	files := []struct {
+		name     string
+		generate func() ([]byte, error)
+	}{
+		{"properties_gen.go", h.Properties},
+		{"getters_gen.go", h.Getters},
+		{"return_gen.go", h.ReturnCodes},
+		{"functions_gen.go", h.FunctionList},
+	}
+
+	for _, file := range files {
+		code, err := file.generate()
+		if err != nil {
+			log.Fatalf("%s: %v", file.name, err)
+		}
+		if err := os.WriteFile(filepath.Join(*out, file.name), code, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
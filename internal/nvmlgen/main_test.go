@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestParamType(t *testing.T) {
+	var tests = []struct {
+		param string
+		typ   string
+	}{
+		{"nvmlDevice_t device", "nvmlDevice_t"},
+		{"unsigned int *speed", "unsigned int*"},
+		{"char* partNumber", "char*"},
+		{"char *buf", "char*"},
+		{"unsigned int length", "unsigned int"},
+		{"void", "void"},
+	}
+
+	for _, test := range tests {
+		if typ := paramType(test.param); typ != test.typ {
+			t.Errorf("paramType(%q) = %q, want %q", test.param, typ, test.typ)
+		}
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	var tests = []struct {
+		name  string
+		camel string
+	}{
+		{"SUCCESS", "Success"},
+		{"GPU_IS_LOST", "GpuIsLost"},
+		{"LIB_RM_VERSION_MISMATCH", "LibRmVersionMismatch"},
+	}
+
+	for _, test := range tests {
+		if camel := CamelCase(test.name); camel != test.camel {
+			t.Errorf("CamelCase(%q) = %q, want %q", test.name, camel, test.camel)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	var tests = []struct {
+		doc     string
+		summary string
+	}{
+		{"Retrieves the name of this device.", "returns the name of this device"},
+		{"Retrieve the PCIe replay counter.", "returns the PCIe replay counter"},
+		{"Get VBIOS version of the device.", "returns VBIOS version of the device"},
+		{"Retrieves power usage (e.g. memory). For Fermi or newer.", "returns power usage (e.g. memory)"},
+		{"Checks the device.", "calls the NVML function that checks the device"},
+	}
+
+	for _, test := range tests {
+		if summary := summary(test.doc); summary != test.summary {
+			t.Errorf("summary(%q) = %q, want %q", test.doc, summary, test.summary)
+		}
+	}
+}
+
+// TestGeneratedUpToDate fails if the generated files weren't regenerated
+// after a change to nvml.h or to nvmlgen
+func TestGeneratedUpToDate(t *testing.T) {
+	src, err := os.ReadFile("../../nvml.h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]func() ([]byte, error){
+		"../../properties_gen.go": h.Properties,
+		"../../getters_gen.go":    h.Getters,
+		"../../return_gen.go":     h.ReturnCodes,
+		"../../functions_gen.go":  h.FunctionList,
+	}
+
+	for name, generate := range files {
+		want, err := generate()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date, run go generate", name)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package nvml
+
+import (
+	"sync"
+	"time"
+)
+
+// Reading is a single value of a metric read by a Monitor
+type Reading struct {
+	Device   *Device
+	Property Property
+	Value    Value
+	Time     time.Time
+}
+
+// Monitor polls a set of devices for a set of metrics at a fixed interval and
+// streams the readings. Each device is polled by its own goroutine with one
+// QueryMany per interval.
+type Monitor struct {
+	devices  []*Device
+	interval time.Duration
+	metrics  []Property
+
+	readings chan Reading
+	errors   chan error
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// monitorErrorBuffer is how many errors are buffered for the consumer before
+// new ones are dropped
+const monitorErrorBuffer = 16
+
+// NewMonitor returns a Monitor for the given metrics of the devices, or for the
+// metrics of DeviceSnapshot if none are given. Call Start to start polling.
+func NewMonitor(devices []*Device, interval time.Duration, metrics ...Property) *Monitor {
+	if len(metrics) == 0 {
+		metrics = snapshotProperties
+	}
+
+	return &Monitor{
+		devices:  devices,
+		interval: interval,
+		metrics:  metrics,
+		readings: make(chan Reading),
+		errors:   make(chan error, monitorErrorBuffer),
+		done:     make(chan struct{}),
+	}
+}
+
+// Readings returns the channel readings are sent on. It is closed once the
+// monitor is stopped.
+func (m *Monitor) Readings() <-chan Reading {
+	return m.readings
+}
+
+// Errors returns the channel errors from polling are sent on. Errors are
+// dropped if the consumer falls behind. It is closed once the monitor is
+// stopped.
+func (m *Monitor) Errors() <-chan error {
+	return m.errors
+}
+
+// Start starts polling. Calling it more than once, or after Stop, has no
+// effect.
+func (m *Monitor) Start() {
+	m.startOnce.Do(func() {
+		for _, gpu := range m.devices {
+			m.wg.Add(1)
+			go m.poll(gpu)
+		}
+	})
+}
+
+// Stop stops polling, waits for the polling goroutines to exit and closes the
+// channels
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() {
+		// Make later calls to Start no-ops, and wait for a concurrent one to
+		// finish adding its goroutines to the wait group
+		m.startOnce.Do(func() {})
+
+		close(m.done)
+		m.wg.Wait()
+		close(m.readings)
+		close(m.errors)
+	})
+}
+
+func (m *Monitor) poll(gpu *Device) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		default:
+		}
+
+		now := time.Now()
+		values, err := gpu.QueryMany(m.metrics...)
+		if err != nil {
+			select {
+			case m.errors <- err:
+			default:
+			}
+		}
+
+		for _, property := range m.metrics {
+			value, ok := values[property]
+			if !ok {
+				continue
+			}
+
+			select {
+			case m.readings <- Reading{Device: gpu, Property: property, Value: value, Time: now}:
+			case <-m.done:
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-m.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package nvml
+
+import (
+	"testing"
+	"time"
+)
+
+// unreachableDevices returns devices whose every query fails, since NVML
+// isn't initialized in unit tests
+func unreachableDevices(t *testing.T, n int) []*Device {
+	t.Helper()
+
+	var devices []*Device
+	for i := 0; i < n; i++ {
+		gpu, err := NewDeviceFromHandle(0, WithLazyProperties())
+		if err != nil {
+			t.Fatal(err)
+		}
+		devices = append(devices, gpu)
+	}
+
+	return devices
+}
+
+// drained waits for the monitor's channels to be closed
+func drained(t *testing.T, m *Monitor) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for readings, errors := m.Readings(), m.Errors(); readings != nil || errors != nil; {
+		select {
+		case _, ok := <-readings:
+			if !ok {
+				readings = nil
+			}
+		case _, ok := <-errors:
+			if !ok {
+				errors = nil
+			}
+		case <-timeout:
+			t.Fatal("monitor channels weren't closed")
+		}
+	}
+}
+
+func TestMonitorReportsErrors(t *testing.T) {
+	m := NewMonitor(unreachableDevices(t, 2), time.Millisecond, PropertyTemperature)
+	m.Start()
+	m.Start()
+
+	select {
+	case err := <-m.Errors():
+		if err == nil {
+			t.Errorf("got a nil error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error reported for unreachable devices")
+	}
+
+	m.Stop()
+	m.Stop()
+	drained(t, m)
+}
+
+func TestMonitorStartAfterStop(t *testing.T) {
+	var tests = []struct {
+		name    string
+		started bool
+	}{
+		{"never started", false},
+		{"started", true},
+	}
+
+	for _, ts := range tests {
+		m := NewMonitor(unreachableDevices(t, 2), time.Millisecond, PropertyTemperature)
+		if ts.started {
+			m.Start()
+		}
+
+		m.Stop()
+		m.Start()
+		drained(t, m)
+
+		// Give goroutines a wrongly restarted monitor would have launched the
+		// chance to send on the closed channels
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestMonitorConcurrentStartStop(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		m := NewMonitor(unreachableDevices(t, 1), time.Millisecond, PropertyTemperature)
+		go m.Start()
+		m.Stop()
+		drained(t, m)
+	}
+}
@@ -0,0 +1,289 @@
+package nvml
+
+// See https://docs.nvidia.com/deploy/nvml-api/group__NvLink.html
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+// NvLinkMaxLinks is the maximum number of NVLink links per device; links are
+// numbered from 0 to NvLinkMaxLinks-1
+const NvLinkMaxLinks = C.NVML_NVLINK_MAX_LINKS
+
+// NvLinkCapability is a capability of an NVLink link
+type NvLinkCapability int
+
+const (
+	NvLinkCapP2PSupported  NvLinkCapability = C.NVML_NVLINK_CAP_P2P_SUPPORTED
+	NvLinkCapSysmemAccess  NvLinkCapability = C.NVML_NVLINK_CAP_SYSMEM_ACCESS
+	NvLinkCapP2PAtomics    NvLinkCapability = C.NVML_NVLINK_CAP_P2P_ATOMICS
+	NvLinkCapSysmemAtomics NvLinkCapability = C.NVML_NVLINK_CAP_SYSMEM_ATOMICS
+	NvLinkCapSLIBridge     NvLinkCapability = C.NVML_NVLINK_CAP_SLI_BRIDGE
+	NvLinkCapValid         NvLinkCapability = C.NVML_NVLINK_CAP_VALID
+)
+
+func (c NvLinkCapability) String() string {
+	switch c {
+	case NvLinkCapP2PSupported:
+		return "P2PSupported"
+	case NvLinkCapSysmemAccess:
+		return "SysmemAccess"
+	case NvLinkCapP2PAtomics:
+		return "P2PAtomics"
+	case NvLinkCapSysmemAtomics:
+		return "SysmemAtomics"
+	case NvLinkCapSLIBridge:
+		return "SLIBridge"
+	case NvLinkCapValid:
+		return "Valid"
+	}
+
+	return fmt.Sprintf("NvLinkCapability(%d)", int(c))
+}
+
+// NvLinkState reports whether the given link is active
+func (gpu *Device) NvLinkState(link uint) (bool, error) {
+	var result C.nvmlReturn_t
+	var cactive C.nvmlEnableState_t
+
+	if err := requireFunction("nvmlDeviceGetNvLinkState"); err != nil {
+		return false, err
+	}
+
+	result = C.nvmlDeviceGetNvLinkState(gpu.handle(), C.uint(link), &cactive)
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceGetNvLinkState", result)
+	}
+
+	return cactive == C.NVML_FEATURE_ENABLED, nil
+}
+
+// NvLinkVersion returns the NVLink version of the given link
+func (gpu *Device) NvLinkVersion(link uint) (uint, error) {
+	var result C.nvmlReturn_t
+	var cversion C.uint
+
+	if err := requireFunction("nvmlDeviceGetNvLinkVersion"); err != nil {
+		return 0, err
+	}
+
+	result = C.nvmlDeviceGetNvLinkVersion(gpu.handle(), C.uint(link), &cversion)
+	if result != C.NVML_SUCCESS {
+		return 0, newNVMLError("nvmlDeviceGetNvLinkVersion", result)
+	}
+
+	return uint(cversion), nil
+}
+
+// NvLinkCapability reports whether the given link has the given capability
+func (gpu *Device) NvLinkCapability(link uint, capability NvLinkCapability) (bool, error) {
+	var result C.nvmlReturn_t
+	var ccapresult C.uint
+
+	if err := requireFunction("nvmlDeviceGetNvLinkCapability"); err != nil {
+		return false, err
+	}
+
+	result = C.nvmlDeviceGetNvLinkCapability(gpu.handle(), C.uint(link), C.nvmlNvLinkCapability_t(capability), &ccapresult)
+	if result != C.NVML_SUCCESS {
+		return false, newNVMLError("nvmlDeviceGetNvLinkCapability", result)
+	}
+
+	return ccapresult != 0, nil
+}
+
+// NvLinkRemotePciInfo returns the PCI attributes of the device at the other
+// end of the given link
+func (gpu *Device) NvLinkRemotePciInfo(link uint) (PciInfo, error) {
+	var result C.nvmlReturn_t
+	var cpciinfo C.nvmlPciInfo_t
+
+	if err := requireFunction("nvmlDeviceGetNvLinkRemotePciInfo"); err != nil {
+		return PciInfo{}, err
+	}
+
+	result = C.nvmlDeviceGetNvLinkRemotePciInfo(gpu.handle(), C.uint(link), &cpciinfo)
+	if result != C.NVML_SUCCESS {
+		return PciInfo{}, newNVMLError("nvmlDeviceGetNvLinkRemotePciInfo", result)
+	}
+
+	return newPciInfo(&cpciinfo), nil
+}
+
+// NvLinkCounterUnit is the unit an NVLink utilization counter counts in
+type NvLinkCounterUnit int
+
+const (
+	NvLinkCounterUnitCycles  NvLinkCounterUnit = C.NVML_NVLINK_COUNTER_UNIT_CYCLES
+	NvLinkCounterUnitPackets NvLinkCounterUnit = C.NVML_NVLINK_COUNTER_UNIT_PACKETS
+	NvLinkCounterUnitBytes   NvLinkCounterUnit = C.NVML_NVLINK_COUNTER_UNIT_BYTES
+)
+
+func (u NvLinkCounterUnit) String() string {
+	switch u {
+	case NvLinkCounterUnitCycles:
+		return "Cycles"
+	case NvLinkCounterUnitPackets:
+		return "Packets"
+	case NvLinkCounterUnitBytes:
+		return "Bytes"
+	}
+
+	return fmt.Sprintf("NvLinkCounterUnit(%d)", int(u))
+}
+
+// NvLinkPacketType is a set of NVLink packet types, combined with bitwise or,
+// that an NVLink utilization counter counts
+type NvLinkPacketType int
+
+const (
+	NvLinkPacketNop        NvLinkPacketType = C.NVML_NVLINK_COUNTER_PKTFILTER_NOP
+	NvLinkPacketRead       NvLinkPacketType = C.NVML_NVLINK_COUNTER_PKTFILTER_READ
+	NvLinkPacketWrite      NvLinkPacketType = C.NVML_NVLINK_COUNTER_PKTFILTER_WRITE
+	NvLinkPacketRatom      NvLinkPacketType = C.NVML_NVLINK_COUNTER_PKTFILTER_RATOM
+	NvLinkPacketNratom     NvLinkPacketType = C.NVML_NVLINK_COUNTER_PKTFILTER_NRATOM
+	NvLinkPacketFlush      NvLinkPacketType = C.NVML_NVLINK_COUNTER_PKTFILTER_FLUSH
+	NvLinkPacketRespData   NvLinkPacketType = C.NVML_NVLINK_COUNTER_PKTFILTER_RESPDATA
+	NvLinkPacketRespNoData NvLinkPacketType = C.NVML_NVLINK_COUNTER_PKTFILTER_RESPNODATA
+	NvLinkPacketAll        NvLinkPacketType = C.NVML_NVLINK_COUNTER_PKTFILTER_ALL
+)
+
+func (t NvLinkPacketType) String() string {
+	switch t {
+	case NvLinkPacketNop:
+		return "Nop"
+	case NvLinkPacketRead:
+		return "Read"
+	case NvLinkPacketWrite:
+		return "Write"
+	case NvLinkPacketRatom:
+		return "Ratom"
+	case NvLinkPacketNratom:
+		return "Nratom"
+	case NvLinkPacketFlush:
+		return "Flush"
+	case NvLinkPacketRespData:
+		return "RespData"
+	case NvLinkPacketRespNoData:
+		return "RespNoData"
+	case NvLinkPacketAll:
+		return "All"
+	}
+
+	return maskString(t, nvLinkPacketTypes)
+}
+
+// Go correspondent of the C.nvmlNvLinkUtilizationControl_t struct. The
+// packet filter only applies when counting in packets or bytes.
+type NvLinkUtilizationControl struct {
+	Units        NvLinkCounterUnit
+	PacketFilter NvLinkPacketType
+}
+
+// SetNvLinkUtilizationControl configures what the given utilization counter
+// (0 or 1) of the link counts, and resets it if reset is true. The counters
+// have no default state, so this should be called before reading them.
+// Requires root.
+func (gpu *Device) SetNvLinkUtilizationControl(link, counter uint, control NvLinkUtilizationControl, reset bool) error {
+	var result C.nvmlReturn_t
+	var ccontrol C.nvmlNvLinkUtilizationControl_t
+	var creset C.uint
+
+	if err := requireFunction("nvmlDeviceSetNvLinkUtilizationControl"); err != nil {
+		return err
+	}
+
+	ccontrol.units = C.nvmlNvLinkUtilizationCountUnits_t(control.Units)
+	ccontrol.pktfilter = C.nvmlNvLinkUtilizationCountPktTypes_t(control.PacketFilter)
+	if reset {
+		creset = 1
+	}
+
+	result = C.nvmlDeviceSetNvLinkUtilizationControl(gpu.handle(), C.uint(link), C.uint(counter), &ccontrol, creset)
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetNvLinkUtilizationControl", result)
+	}
+
+	return nil
+}
+
+// NvLinkUtilizationControl returns what the given utilization counter (0 or 1)
+// of the link is configured to count
+func (gpu *Device) NvLinkUtilizationControl(link, counter uint) (NvLinkUtilizationControl, error) {
+	var result C.nvmlReturn_t
+	var ccontrol C.nvmlNvLinkUtilizationControl_t
+	var control NvLinkUtilizationControl
+
+	if err := requireFunction("nvmlDeviceGetNvLinkUtilizationControl"); err != nil {
+		return control, err
+	}
+
+	result = C.nvmlDeviceGetNvLinkUtilizationControl(gpu.handle(), C.uint(link), C.uint(counter), &ccontrol)
+	if result != C.NVML_SUCCESS {
+		return control, newNVMLError("nvmlDeviceGetNvLinkUtilizationControl", result)
+	}
+
+	control.Units = NvLinkCounterUnit(ccontrol.units)
+	control.PacketFilter = NvLinkPacketType(ccontrol.pktfilter)
+
+	return control, nil
+}
+
+// NvLinkUtilizationCounter returns the receive and transmit values of the
+// given utilization counter (0 or 1) of the link, in the units it was
+// configured with by SetNvLinkUtilizationControl
+func (gpu *Device) NvLinkUtilizationCounter(link, counter uint) (rx, tx uint64, err error) {
+	var result C.nvmlReturn_t
+	var crx, ctx C.ulonglong
+
+	if err := requireFunction("nvmlDeviceGetNvLinkUtilizationCounter"); err != nil {
+		return 0, 0, err
+	}
+
+	result = C.nvmlDeviceGetNvLinkUtilizationCounter(gpu.handle(), C.uint(link), C.uint(counter), &crx, &ctx)
+	if result != C.NVML_SUCCESS {
+		return 0, 0, newNVMLError("nvmlDeviceGetNvLinkUtilizationCounter", result)
+	}
+
+	return uint64(crx), uint64(ctx), nil
+}
+
+// FreezeNvLinkUtilizationCounter freezes, or unfreezes, both the receive and
+// transmit values of the given utilization counter (0 or 1) of the link.
+// Requires root.
+func (gpu *Device) FreezeNvLinkUtilizationCounter(link, counter uint, freeze bool) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceFreezeNvLinkUtilizationCounter"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceFreezeNvLinkUtilizationCounter(gpu.handle(), C.uint(link), C.uint(counter), enableState(freeze))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceFreezeNvLinkUtilizationCounter", result)
+	}
+
+	return nil
+}
+
+// ResetNvLinkUtilizationCounter resets both the receive and transmit values of
+// the given utilization counter (0 or 1) of the link. Requires root.
+func (gpu *Device) ResetNvLinkUtilizationCounter(link, counter uint) error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceResetNvLinkUtilizationCounter"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceResetNvLinkUtilizationCounter(gpu.handle(), C.uint(link), C.uint(counter))
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceResetNvLinkUtilizationCounter", result)
+	}
+
+	return nil
+}
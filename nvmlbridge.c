@@ -1,4 +1,11 @@
 
+#ifndef _WINDOWS
+#define _GNU_SOURCE
+#include <dlfcn.h>
+#else
+#include <windows.h>
+#endif
+
 #include "nvmlbridge.h"
 #include <stdlib.h>
 
@@ -11,11 +18,7 @@ int bridge_get_text_property(gettextProperty f,
 
     ret = f(device, buf, length);
 
-    if (ret == NVML_SUCCESS) {
-        return(EXIT_SUCCESS);
-    } else {
-        return(EXIT_FAILURE);
-    }
+    return(ret);
 }
 
 int bridge_get_int_property(getintProperty f,
@@ -26,10 +29,117 @@ int bridge_get_int_property(getintProperty f,
 
     ret = f(device, property);
 
-    if (ret == NVML_SUCCESS) {
-        return(EXIT_SUCCESS);
-    } else {
-        return(EXIT_FAILURE);
+    return(ret);
+}
+
+
+static nvmlReturn_t query_one(nvmlDevice_t device,
+                              int prop,
+                              unsigned long long *value)
+{
+    nvmlReturn_t ret;
+    unsigned int uintval = 0;
+    unsigned int sampling = 0;
+    nvmlPstates_t pstate;
+    nvmlUtilization_t utilization;
+    nvmlMemory_t memory;
+
+    switch (prop) {
+    case BRIDGE_PROP_TEMPERATURE:
+        ret = nvmlDeviceGetTemperature(device, NVML_TEMPERATURE_GPU, &uintval);
+        break;
+    case BRIDGE_PROP_POWER_USAGE:
+        ret = nvmlDeviceGetPowerUsage(device, &uintval);
+        break;
+    case BRIDGE_PROP_ENFORCED_POWER_LIMIT:
+        ret = nvmlDeviceGetEnforcedPowerLimit(device, &uintval);
+        break;
+    case BRIDGE_PROP_FAN_SPEED:
+        ret = nvmlDeviceGetFanSpeed(device, &uintval);
+        break;
+    case BRIDGE_PROP_PERFORMANCE_STATE:
+        ret = nvmlDeviceGetPerformanceState(device, &pstate);
+        uintval = (unsigned int)pstate;
+        break;
+    case BRIDGE_PROP_CLOCK_GRAPHICS:
+        ret = nvmlDeviceGetClockInfo(device, NVML_CLOCK_GRAPHICS, &uintval);
+        break;
+    case BRIDGE_PROP_CLOCK_SM:
+        ret = nvmlDeviceGetClockInfo(device, NVML_CLOCK_SM, &uintval);
+        break;
+    case BRIDGE_PROP_CLOCK_MEM:
+        ret = nvmlDeviceGetClockInfo(device, NVML_CLOCK_MEM, &uintval);
+        break;
+    case BRIDGE_PROP_CLOCK_VIDEO:
+        ret = nvmlDeviceGetClockInfo(device, NVML_CLOCK_VIDEO, &uintval);
+        break;
+    case BRIDGE_PROP_UTILIZATION_GPU:
+        ret = nvmlDeviceGetUtilizationRates(device, &utilization);
+        uintval = utilization.gpu;
+        break;
+    case BRIDGE_PROP_UTILIZATION_MEMORY:
+        ret = nvmlDeviceGetUtilizationRates(device, &utilization);
+        uintval = utilization.memory;
+        break;
+    case BRIDGE_PROP_ENCODER_UTILIZATION:
+        ret = nvmlDeviceGetEncoderUtilization(device, &uintval, &sampling);
+        break;
+    case BRIDGE_PROP_DECODER_UTILIZATION:
+        ret = nvmlDeviceGetDecoderUtilization(device, &uintval, &sampling);
+        break;
+    case BRIDGE_PROP_MEMORY_TOTAL:
+        ret = nvmlDeviceGetMemoryInfo(device, &memory);
+        *value = memory.total;
+        return(ret);
+    case BRIDGE_PROP_MEMORY_FREE:
+        ret = nvmlDeviceGetMemoryInfo(device, &memory);
+        *value = memory.free;
+        return(ret);
+    case BRIDGE_PROP_MEMORY_USED:
+        ret = nvmlDeviceGetMemoryInfo(device, &memory);
+        *value = memory.used;
+        return(ret);
+    case BRIDGE_PROP_PCIE_REPLAY_COUNTER:
+        ret = nvmlDeviceGetPcieReplayCounter(device, &uintval);
+        break;
+    default:
+        return(NVML_ERROR_INVALID_ARGUMENT);
+    }
+
+    *value = uintval;
+    return(ret);
+}
+
+void bridge_query_many(nvmlDevice_t device,
+                       const int *props,
+                       unsigned int count,
+                       unsigned long long *values,
+                       int *results)
+{
+    unsigned int i;
+
+    for (i = 0; i < count; i++) {
+        values[i] = 0;
+        results[i] = query_one(device, props[i], &values[i]);
     }
 }
 
+void *bridge_function(const char *name)
+{
+#ifdef _WINDOWS
+    HMODULE lib = GetModuleHandleA("nvml.dll");
+
+    if (lib == NULL) {
+        return(NULL);
+    }
+
+    return((void *)GetProcAddress(lib, name));
+#else
+    return(dlsym(RTLD_DEFAULT, name));
+#endif
+}
+
+int bridge_has_function(const char *name)
+{
+    return(bridge_function(name) != NULL);
+}
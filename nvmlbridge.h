@@ -6,7 +6,8 @@
 // Not every function can be genericized in this way because of all the custom structs,
 // but there are several nvmlGet functions we want that take a nvmlDevice_t, *char, and
 // a length as arguments. These are trivial to pass as function pointers along with their,
-// arguments, so we might as well save some effort.
+// arguments, so we might as well save some effort. The bridges return the
+// nvmlReturn_t of the wrapped function.
 typedef int (*gettextProperty) (nvmlDevice_t device , char *buf, unsigned int length);
 int bridge_get_text_property(gettextProperty f,
                              nvmlDevice_t device,
@@ -18,3 +19,46 @@ typedef int (*getintProperty) (nvmlDevice_t device , unsigned int *property);
 int bridge_get_int_property(getintProperty f,
                              nvmlDevice_t device,
                              unsigned int *property);
+
+// Properties that bridge_query_many can fetch. Their values are mirrored by the
+// Property constants on the Go side.
+typedef enum bridge_property_enum {
+    BRIDGE_PROP_TEMPERATURE = 0,
+    BRIDGE_PROP_POWER_USAGE,
+    BRIDGE_PROP_ENFORCED_POWER_LIMIT,
+    BRIDGE_PROP_FAN_SPEED,
+    BRIDGE_PROP_PERFORMANCE_STATE,
+    BRIDGE_PROP_CLOCK_GRAPHICS,
+    BRIDGE_PROP_CLOCK_SM,
+    BRIDGE_PROP_CLOCK_MEM,
+    BRIDGE_PROP_CLOCK_VIDEO,
+    BRIDGE_PROP_UTILIZATION_GPU,
+    BRIDGE_PROP_UTILIZATION_MEMORY,
+    BRIDGE_PROP_ENCODER_UTILIZATION,
+    BRIDGE_PROP_DECODER_UTILIZATION,
+    BRIDGE_PROP_MEMORY_TOTAL,
+    BRIDGE_PROP_MEMORY_FREE,
+    BRIDGE_PROP_MEMORY_USED,
+    BRIDGE_PROP_PCIE_REPLAY_COUNTER,
+    BRIDGE_PROP_COUNT
+} bridge_property_t;
+
+// Fetches count properties of a device in one go, so that callers polling many
+// metrics only cross from Go into C once. The value and nvmlReturn_t of
+// props[i] are stored in values[i] and results[i].
+void bridge_query_many(nvmlDevice_t device,
+                       const int *props,
+                       unsigned int count,
+                       unsigned long long *values,
+                       int *results);
+
+// Returns the address of the named function in the loaded NVML library, or
+// NULL if it doesn't export it. The getter tables are resolved this way, since
+// taking the address of a function directly would keep the program from
+// loading on drivers that lack it.
+void *bridge_function(const char *name);
+
+// Reports whether the loaded NVML library exports the named function. Older
+// drivers lack functions declared in newer headers, and calling one of those
+// would abort the process, so every wrapper checks first.
+int bridge_has_function(const char *name);
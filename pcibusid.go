@@ -0,0 +1,113 @@
+package nvml
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// PCIBusID is a PCI address, domain:bus:device.function
+type PCIBusID struct {
+	Domain   uint32
+	Bus      uint8
+	Device   uint8
+	Function uint8
+}
+
+// ParsePCIBusID parses a PCI bus ID. NVML's "00000000:81:00.0", the kernel's
+// "0000:81:00.0" and the legacy short forms "81:00.0" and "81:00" are all
+// accepted, in either case. The domain and function default to 0.
+func ParsePCIBusID(s string) (PCIBusID, error) {
+	var id PCIBusID
+
+	rest := s
+	if i := strings.LastIndexByte(rest, '.'); i >= 0 {
+		function, err := strconv.ParseUint(rest[i+1:], 16, 3)
+		if err != nil {
+			return id, fmt.Errorf("invalid PCI bus ID %q", s)
+		}
+		id.Function = uint8(function)
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) == 3 {
+		domain, err := strconv.ParseUint(parts[0], 16, 32)
+		if err != nil {
+			return id, fmt.Errorf("invalid PCI bus ID %q", s)
+		}
+		id.Domain = uint32(domain)
+		parts = parts[1:]
+	}
+	if len(parts) != 2 || len(parts[0]) > 2 || len(parts[1]) > 2 {
+		return id, fmt.Errorf("invalid PCI bus ID %q", s)
+	}
+
+	bus, err := strconv.ParseUint(parts[0], 16, 8)
+	if err != nil {
+		return id, fmt.Errorf("invalid PCI bus ID %q", s)
+	}
+	device, err := strconv.ParseUint(parts[1], 16, 5)
+	if err != nil {
+		return id, fmt.Errorf("invalid PCI bus ID %q", s)
+	}
+	id.Bus = uint8(bus)
+	id.Device = uint8(device)
+
+	return id, nil
+}
+
+// String formats the ID in the canonical form used by the kernel, e.g. in
+// /sys/bus/pci/devices, and by libvirt: "0000:81:00.0"
+func (id PCIBusID) String() string {
+	return fmt.Sprintf("%04x:%02x:%02x.%x", id.Domain, id.Bus, id.Device, id.Function)
+}
+
+// Compare orders IDs by domain, bus, device and function, returning -1, 0 or
+// +1
+func (id PCIBusID) Compare(other PCIBusID) int {
+	a := []uint32{id.Domain, uint32(id.Bus), uint32(id.Device), uint32(id.Function)}
+	b := []uint32{other.Domain, uint32(other.Bus), uint32(other.Device), uint32(other.Function)}
+
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// PCIBusID returns the parsed bus ID of the device
+func (pciinfo PciInfo) PCIBusID() (PCIBusID, error) {
+	return ParsePCIBusID(pciinfo.BusID)
+}
+
+// DeviceByPCIBusID returns the device at the given PCI bus ID
+func DeviceByPCIBusID(id PCIBusID, opts ...DeviceOption) (*Device, error) {
+	var result C.nvmlReturn_t
+	var cdevice C.nvmlDevice_t
+
+	if err := requireFunction("nvmlDeviceGetHandleByPciBusId"); err != nil {
+		return nil, err
+	}
+
+	cbusid := C.CString(id.String())
+	defer C.free(unsafe.Pointer(cbusid))
+
+	result = C.nvmlDeviceGetHandleByPciBusId(cbusid, &cdevice)
+	if result != C.NVML_SUCCESS {
+		return nil, newNVMLError("nvmlDeviceGetHandleByPciBusId", result)
+	}
+
+	return NewDevice(cdevice, opts...)
+}
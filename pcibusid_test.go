@@ -0,0 +1,55 @@
+package nvml
+
+import (
+	"testing"
+)
+
+func TestParsePCIBusID(t *testing.T) {
+	var tests = []struct {
+		in  string
+		out string
+		err bool
+	}{
+		{"0000:81:00.0", "0000:81:00.0", false},
+		{"00000000:81:00.0", "0000:81:00.0", false},
+		{"0001:AF:1F.7", "0001:af:1f.7", false},
+		{"81:00.0", "0000:81:00.0", false},
+		{"81:00", "0000:81:00.0", false},
+		{"81", "", true},
+		{"0000:181:00.0", "", true},
+		{"0000:81:20.0", "", true},
+		{"0000:81:00.8", "", true},
+		{"x:81:00.0", "", true},
+	}
+
+	for _, ts := range tests {
+		id, err := ParsePCIBusID(ts.in)
+		if (err != nil) != ts.err {
+			t.Errorf("ParsePCIBusID(%q) error = %v", ts.in, err)
+			continue
+		}
+		if err == nil && id.String() != ts.out {
+			t.Errorf("ParsePCIBusID(%q) = %s, expected %s", ts.in, id, ts.out)
+		}
+	}
+}
+
+func TestPCIBusIDCompare(t *testing.T) {
+	var tests = []struct {
+		a, b string
+		cmp  int
+	}{
+		{"0000:81:00.0", "81:00.0", 0},
+		{"0000:04:00.0", "0000:81:00.0", -1},
+		{"0001:00:00.0", "0000:ff:1f.7", 1},
+		{"0000:81:00.1", "0000:81:00.0", 1},
+	}
+
+	for _, ts := range tests {
+		a, _ := ParsePCIBusID(ts.a)
+		b, _ := ParsePCIBusID(ts.b)
+		if cmp := a.Compare(b); cmp != ts.cmp {
+			t.Errorf("%s.Compare(%s) = %d, expected %d", ts.a, ts.b, cmp, ts.cmp)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package nvml
+
+// See https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+// Go correspondent of the C.nvmlProcessInfo_t struct
+type ProcessInfo struct {
+	PID           uint
+	UsedGpuMemory uint64 // bytes; not available under WDDM
+}
+
+// processSlack is how many extra entries to allocate, in case processes start
+// between sizing the buffer and filling it
+const processSlack = 8
+
+type runningProcessesFunc func(C.nvmlDevice_t, *C.uint, *C.nvmlProcessInfo_t) C.nvmlReturn_t
+
+// runningProcesses calls one of the nvmlDeviceGet*RunningProcesses functions
+func (gpu *Device) runningProcesses(f runningProcessesFunc, name string) ([]ProcessInfo, error) {
+	var result C.nvmlReturn_t
+	var ccount C.uint
+	var processes []ProcessInfo
+
+	if err := requireFunction(name); err != nil {
+		return processes, err
+	}
+
+	result = f(gpu.handle(), &ccount, nil)
+	if result == C.NVML_SUCCESS {
+		return processes, nil
+	}
+	if result != C.NVML_ERROR_INSUFFICIENT_SIZE {
+		return processes, newNVMLError(name, result)
+	}
+
+	ccount += processSlack
+	cinfos := make([]C.nvmlProcessInfo_t, ccount)
+	result = f(gpu.handle(), &ccount, &cinfos[0])
+	if result != C.NVML_SUCCESS {
+		return processes, newNVMLError(name, result)
+	}
+
+	for i := range cinfos[:ccount] {
+		processes = append(processes, ProcessInfo{
+			PID:           uint(cinfos[i].pid),
+			UsedGpuMemory: uint64(cinfos[i].usedGpuMemory),
+		})
+	}
+
+	return processes, nil
+}
+
+// ComputeRunningProcesses returns the processes with a compute context on the
+// device
+func (gpu *Device) ComputeRunningProcesses() ([]ProcessInfo, error) {
+	return gpu.runningProcesses(func(d C.nvmlDevice_t, c *C.uint, i *C.nvmlProcessInfo_t) C.nvmlReturn_t {
+		return C.nvmlDeviceGetComputeRunningProcesses(d, c, i)
+	}, "nvmlDeviceGetComputeRunningProcesses")
+}
+
+// GraphicsRunningProcesses returns the processes with a graphics context on
+// the device
+func (gpu *Device) GraphicsRunningProcesses() ([]ProcessInfo, error) {
+	return gpu.runningProcesses(func(d C.nvmlDevice_t, c *C.uint, i *C.nvmlProcessInfo_t) C.nvmlReturn_t {
+		return C.nvmlDeviceGetGraphicsRunningProcesses(d, c, i)
+	}, "nvmlDeviceGetGraphicsRunningProcesses")
+}
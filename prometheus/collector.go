@@ -0,0 +1,158 @@
+// Package prometheus exports NVML device metrics to Prometheus.
+//
+//	devices, _ := nvml.GetAllGPUs()
+//	prometheus.MustRegister(nvmlprometheus.NewCollector(devices))
+package prometheus
+
+import (
+	"strconv"
+
+	nvml "github.com/davidr/go-nvml"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "nvml"
+
+var deviceLabels = []string{"uuid", "index", "name"}
+
+func newDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "gpu", name), help, append(deviceLabels, labels...), nil)
+}
+
+var (
+	temperatureDesc = newDesc("temperature_celsius", "GPU core temperature.")
+	powerUsageDesc  = newDesc("power_usage_watts", "Power drawn by the GPU and its memory.")
+	powerLimitDesc  = newDesc("power_limit_watts", "Power limit enforced by the driver.")
+	fanSpeedDesc    = newDesc("fan_speed_ratio", "Intended fan speed, as a fraction of its maximum.")
+	clockDesc       = newDesc("clock_hertz", "Current clock speed.", "clock")
+	memoryTotalDesc = newDesc("memory_total_bytes", "Total device memory.")
+	memoryUsedDesc  = newDesc("memory_used_bytes", "Allocated device memory.")
+	utilizationDesc = newDesc("utilization_ratio", "Fraction of the last sample period the GPU or its memory was busy.", "unit")
+	eccErrorsDesc   = newDesc("ecc_errors_total", "ECC errors over the lifetime of the device.", "type")
+	throttleDesc    = newDesc("clocks_throttle_reason", "Whether the clocks are being throttled for the given reason.", "reason")
+	upDesc          = newDesc("up", "Whether the device could be queried.")
+)
+
+// queriedProperties are the metrics fetched with a single QueryMany per device
+var queriedProperties = []nvml.Property{
+	nvml.PropertyTemperature,
+	nvml.PropertyPowerUsage,
+	nvml.PropertyEnforcedPowerLimit,
+	nvml.PropertyFanSpeed,
+	nvml.PropertyClockGraphics,
+	nvml.PropertyClockSM,
+	nvml.PropertyClockMem,
+	nvml.PropertyClockVideo,
+	nvml.PropertyMemoryTotal,
+	nvml.PropertyMemoryUsed,
+	nvml.PropertyUtilizationGPU,
+	nvml.PropertyUtilizationMemory,
+}
+
+// Collector is a prometheus.Collector for a set of NVML devices. Metrics a
+// device doesn't support are left out, but nvml_gpu_up is always reported, as
+// 0 for devices that can't be queried at all.
+type Collector struct {
+	devices []*nvml.Device
+}
+
+// NewCollector returns a Collector for the given devices. NVML must stay
+// initialized while it is registered.
+func NewCollector(devices []*nvml.Device) *Collector {
+	return &Collector{devices: devices}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		temperatureDesc, powerUsageDesc, powerLimitDesc, fanSpeedDesc, clockDesc,
+		memoryTotalDesc, memoryUsedDesc, utilizationDesc, eccErrorsDesc, throttleDesc, upDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for position, gpu := range c.devices {
+		c.collectDevice(ch, gpu, position)
+	}
+}
+
+// labelValues returns the device labels of gpu. They come from the identity
+// cached on the Device, so a lost GPU keeps its labels. Devices constructed
+// with lazy properties are queried instead, and fall back to their position
+// in the collector's device list for the index.
+func labelValues(gpu *nvml.Device, position int) []string {
+	uuid, name, index := gpu.Identity()
+	if uuid == "" {
+		uuid, _ = gpu.UUID()
+		name, _ = gpu.Name()
+		if i, err := gpu.Index(); err == nil {
+			index = i
+		} else {
+			index = uint(position)
+		}
+	}
+
+	return []string{uuid, strconv.FormatUint(uint64(index), 10), name}
+}
+
+func (c *Collector) collectDevice(ch chan<- prometheus.Metric, gpu *nvml.Device, position int) {
+	labels := labelValues(gpu, position)
+
+	gauge := func(desc *prometheus.Desc, value float64, extra ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, append(labels, extra...)...)
+	}
+
+	values, err := gpu.QueryMany(queriedProperties...)
+	if len(values) == 0 && err != nil {
+		gauge(upDesc, 0)
+		return
+	}
+	gauge(upDesc, 1)
+
+	type metric struct {
+		desc  *prometheus.Desc
+		scale float64
+		extra []string
+	}
+	for property, m := range map[nvml.Property]metric{
+		nvml.PropertyTemperature:        {temperatureDesc, 1, nil},
+		nvml.PropertyPowerUsage:         {powerUsageDesc, 1e-3, nil},
+		nvml.PropertyEnforcedPowerLimit: {powerLimitDesc, 1e-3, nil},
+		nvml.PropertyFanSpeed:           {fanSpeedDesc, 1e-2, nil},
+		nvml.PropertyClockGraphics:      {clockDesc, 1e6, []string{"graphics"}},
+		nvml.PropertyClockSM:            {clockDesc, 1e6, []string{"sm"}},
+		nvml.PropertyClockMem:           {clockDesc, 1e6, []string{"mem"}},
+		nvml.PropertyClockVideo:         {clockDesc, 1e6, []string{"video"}},
+		nvml.PropertyMemoryTotal:        {memoryTotalDesc, 1, nil},
+		nvml.PropertyMemoryUsed:         {memoryUsedDesc, 1, nil},
+		nvml.PropertyUtilizationGPU:     {utilizationDesc, 1e-2, []string{"gpu"}},
+		nvml.PropertyUtilizationMemory:  {utilizationDesc, 1e-2, []string{"memory"}},
+	} {
+		if value, ok := values[property]; ok {
+			gauge(m.desc, float64(value)*m.scale, m.extra...)
+		}
+	}
+
+	for errorType, label := range map[nvml.MemoryErrorType]string{
+		nvml.MemoryErrorCorrected:   "corrected",
+		nvml.MemoryErrorUncorrected: "uncorrected",
+	} {
+		count, err := gpu.TotalEccErrors(errorType, nvml.EccCounterAggregate)
+		if err == nil {
+			ch <- prometheus.MustNewConstMetric(eccErrorsDesc, prometheus.CounterValue, float64(count), append(labels, label)...)
+		}
+	}
+
+	if reasons, err := gpu.CurrentClocksThrottleReasons(); err == nil {
+		for _, reason := range nvml.ClocksThrottleReasons {
+			active := 0.0
+			if reasons&reason != 0 {
+				active = 1
+			}
+			gauge(throttleDesc, active, reason.String())
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package prometheus
+
+import (
+	"testing"
+
+	nvml "github.com/davidr/go-nvml"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// collect runs the collector and returns the metrics it produced for desc
+func collect(t *testing.T, c *Collector, desc *prometheus.Desc) []*dto.Metric {
+	t.Helper()
+
+	ch := make(chan prometheus.Metric, 1000)
+	c.Collect(ch)
+	close(ch)
+
+	var metrics []*dto.Metric
+	for m := range ch {
+		if m.Desc() != desc {
+			continue
+		}
+
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil {
+			t.Fatal(err)
+		}
+		metrics = append(metrics, &pb)
+	}
+
+	return metrics
+}
+
+func labelMap(m *dto.Metric) map[string]string {
+	labels := make(map[string]string)
+	for _, pair := range m.GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+
+	return labels
+}
+
+// TestCollectUnreachableDevice checks that a device that can't be queried is
+// still reported, as down, instead of disappearing from the scrape. NVML
+// isn't initialized, so every query fails.
+func TestCollectUnreachableDevice(t *testing.T) {
+	var devices []*nvml.Device
+	for i := 0; i < 2; i++ {
+		gpu, err := nvml.NewDeviceFromHandle(0, nvml.WithLazyProperties())
+		if err != nil {
+			t.Fatal(err)
+		}
+		devices = append(devices, gpu)
+	}
+
+	up := collect(t, NewCollector(devices), upDesc)
+	if len(up) != len(devices) {
+		t.Fatalf("got %d up metrics, expected %d", len(up), len(devices))
+	}
+
+	for i, m := range up {
+		if m.GetGauge().GetValue() != 0 {
+			t.Errorf("device %d: up = %v, expected 0", i, m.GetGauge().GetValue())
+		}
+
+		labels := labelMap(m)
+		var tests = []struct {
+			label string
+			value string
+		}{
+			{"uuid", ""},
+			{"name", ""},
+			{"index", []string{"0", "1"}[i]},
+		}
+		for _, ts := range tests {
+			if labels[ts.label] != ts.value {
+				t.Errorf("device %d: label %s = %q, expected %q", i, ts.label, labels[ts.label], ts.value)
+			}
+		}
+	}
+
+	if temperature := collect(t, NewCollector(devices), temperatureDesc); len(temperature) != 0 {
+		t.Errorf("got %d temperature metrics for unreachable devices", len(temperature))
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	ch := make(chan *prometheus.Desc, 100)
+	NewCollector(nil).Describe(ch)
+	close(ch)
+
+	seen := make(map[*prometheus.Desc]bool)
+	for desc := range ch {
+		seen[desc] = true
+	}
+
+	for _, desc := range []*prometheus.Desc{temperatureDesc, powerUsageDesc, clockDesc, eccErrorsDesc, throttleDesc, upDesc} {
+		if !seen[desc] {
+			t.Errorf("Describe didn't send %s", desc)
+		}
+	}
+}
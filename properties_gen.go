@@ -0,0 +1,36 @@
+// Code generated by nvmlgen from nvml.h; DO NOT EDIT.
+
+package nvml
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+var intpropfunctions = map[string]*cIntPropFunc{
+	"Index":                        {symbol: "nvmlDeviceGetIndex"},
+	"MinorNumber":                  {symbol: "nvmlDeviceGetMinorNumber"},
+	"InforomConfigurationChecksum": {symbol: "nvmlDeviceGetInforomConfigurationChecksum"},
+	"MaxPcieLinkGeneration":        {symbol: "nvmlDeviceGetMaxPcieLinkGeneration"},
+	"MaxPcieLinkWidth":             {symbol: "nvmlDeviceGetMaxPcieLinkWidth"},
+	"CurrPcieLinkGeneration":       {symbol: "nvmlDeviceGetCurrPcieLinkGeneration"},
+	"CurrPcieLinkWidth":            {symbol: "nvmlDeviceGetCurrPcieLinkWidth"},
+	"PcieReplayCounter":            {symbol: "nvmlDeviceGetPcieReplayCounter"},
+	"FanSpeed":                     {symbol: "nvmlDeviceGetFanSpeed"},
+	"PowerManagementLimit":         {symbol: "nvmlDeviceGetPowerManagementLimit"},
+	"PowerManagementDefaultLimit":  {symbol: "nvmlDeviceGetPowerManagementDefaultLimit"},
+	"PowerUsage":                   {symbol: "nvmlDeviceGetPowerUsage"},
+	"EnforcedPowerLimit":           {symbol: "nvmlDeviceGetEnforcedPowerLimit"},
+	"BoardId":                      {symbol: "nvmlDeviceGetBoardId"},
+	"MultiGpuBoard":                {symbol: "nvmlDeviceGetMultiGpuBoard"},
+	"AccountingBufferSize":         {symbol: "nvmlDeviceGetAccountingBufferSize"},
+}
+
+var textpropfunctions = map[string]*cTextPropFunc{
+	"Name":                {symbol: "nvmlDeviceGetName", length: C.NVML_DEVICE_NAME_BUFFER_SIZE},
+	"Serial":              {symbol: "nvmlDeviceGetSerial", length: C.NVML_DEVICE_SERIAL_BUFFER_SIZE},
+	"UUID":                {symbol: "nvmlDeviceGetUUID", length: C.NVML_DEVICE_UUID_BUFFER_SIZE},
+	"BoardPartNumber":     {symbol: "nvmlDeviceGetBoardPartNumber", length: C.NVML_DEVICE_PART_NUMBER_BUFFER_SIZE},
+	"InforomImageVersion": {symbol: "nvmlDeviceGetInforomImageVersion", length: C.NVML_DEVICE_INFOROM_VERSION_BUFFER_SIZE},
+	"VbiosVersion":        {symbol: "nvmlDeviceGetVbiosVersion", length: C.NVML_DEVICE_VBIOS_VERSION_BUFFER_SIZE},
+}
@@ -0,0 +1,167 @@
+package nvml
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Property is a dynamic metric that can be fetched with QueryMany
+type Property int
+
+const (
+	PropertyTemperature        Property = C.BRIDGE_PROP_TEMPERATURE          // °C
+	PropertyPowerUsage         Property = C.BRIDGE_PROP_POWER_USAGE          // mW
+	PropertyEnforcedPowerLimit Property = C.BRIDGE_PROP_ENFORCED_POWER_LIMIT // mW
+	PropertyFanSpeed           Property = C.BRIDGE_PROP_FAN_SPEED            // %
+	PropertyPerformanceState   Property = C.BRIDGE_PROP_PERFORMANCE_STATE    // Pstate
+	PropertyClockGraphics      Property = C.BRIDGE_PROP_CLOCK_GRAPHICS       // MHz
+	PropertyClockSM            Property = C.BRIDGE_PROP_CLOCK_SM             // MHz
+	PropertyClockMem           Property = C.BRIDGE_PROP_CLOCK_MEM            // MHz
+	PropertyClockVideo         Property = C.BRIDGE_PROP_CLOCK_VIDEO          // MHz
+	PropertyUtilizationGPU     Property = C.BRIDGE_PROP_UTILIZATION_GPU      // %
+	PropertyUtilizationMemory  Property = C.BRIDGE_PROP_UTILIZATION_MEMORY   // %
+	PropertyEncoderUtilization Property = C.BRIDGE_PROP_ENCODER_UTILIZATION  // %
+	PropertyDecoderUtilization Property = C.BRIDGE_PROP_DECODER_UTILIZATION  // %
+	PropertyMemoryTotal        Property = C.BRIDGE_PROP_MEMORY_TOTAL         // bytes
+	PropertyMemoryFree         Property = C.BRIDGE_PROP_MEMORY_FREE          // bytes
+	PropertyMemoryUsed         Property = C.BRIDGE_PROP_MEMORY_USED          // bytes
+	PropertyPCIeReplayCounter  Property = C.BRIDGE_PROP_PCIE_REPLAY_COUNTER
+)
+
+var propertyNames = map[Property]string{
+	PropertyTemperature:        "Temperature",
+	PropertyPowerUsage:         "PowerUsage",
+	PropertyEnforcedPowerLimit: "EnforcedPowerLimit",
+	PropertyFanSpeed:           "FanSpeed",
+	PropertyPerformanceState:   "PerformanceState",
+	PropertyClockGraphics:      "ClockGraphics",
+	PropertyClockSM:            "ClockSM",
+	PropertyClockMem:           "ClockMem",
+	PropertyClockVideo:         "ClockVideo",
+	PropertyUtilizationGPU:     "UtilizationGPU",
+	PropertyUtilizationMemory:  "UtilizationMemory",
+	PropertyEncoderUtilization: "EncoderUtilization",
+	PropertyDecoderUtilization: "DecoderUtilization",
+	PropertyMemoryTotal:        "MemoryTotal",
+	PropertyMemoryFree:         "MemoryFree",
+	PropertyMemoryUsed:         "MemoryUsed",
+	PropertyPCIeReplayCounter:  "PCIeReplayCounter",
+}
+
+// propertyFunctions are the NVML functions the properties are fetched with
+var propertyFunctions = map[Property]string{
+	PropertyTemperature:        "nvmlDeviceGetTemperature",
+	PropertyPowerUsage:         "nvmlDeviceGetPowerUsage",
+	PropertyEnforcedPowerLimit: "nvmlDeviceGetEnforcedPowerLimit",
+	PropertyFanSpeed:           "nvmlDeviceGetFanSpeed",
+	PropertyPerformanceState:   "nvmlDeviceGetPerformanceState",
+	PropertyClockGraphics:      "nvmlDeviceGetClockInfo",
+	PropertyClockSM:            "nvmlDeviceGetClockInfo",
+	PropertyClockMem:           "nvmlDeviceGetClockInfo",
+	PropertyClockVideo:         "nvmlDeviceGetClockInfo",
+	PropertyUtilizationGPU:     "nvmlDeviceGetUtilizationRates",
+	PropertyUtilizationMemory:  "nvmlDeviceGetUtilizationRates",
+	PropertyEncoderUtilization: "nvmlDeviceGetEncoderUtilization",
+	PropertyDecoderUtilization: "nvmlDeviceGetDecoderUtilization",
+	PropertyMemoryTotal:        "nvmlDeviceGetMemoryInfo",
+	PropertyMemoryFree:         "nvmlDeviceGetMemoryInfo",
+	PropertyMemoryUsed:         "nvmlDeviceGetMemoryInfo",
+	PropertyPCIeReplayCounter:  "nvmlDeviceGetPcieReplayCounter",
+}
+
+func (p Property) String() string {
+	if name, ok := propertyNames[p]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Property(%d)", int(p))
+}
+
+// Value is the value of a Property, in the unit documented on its constant
+type Value uint64
+
+// QueryMany fetches the given properties in a single call into C, which is
+// considerably cheaper than calling the individual getters when polling many
+// metrics. Properties that can't be fetched are left out of the result and
+// their errors joined. Transient failures are retried if the device has a
+// retry policy.
+func (gpu *Device) QueryMany(props ...Property) (map[Property]Value, error) {
+	values := make(map[Property]Value, len(props))
+	if len(props) == 0 {
+		return values, nil
+	}
+
+	// Only the properties that failed transiently are queried again, and
+	// those whose function the driver lacks aren't queried at all
+	var pending []Property
+	errs := make(map[Property]error)
+	for _, prop := range props {
+		if function, ok := propertyFunctions[prop]; ok {
+			if err := requireFunction(function); err != nil {
+				errs[prop] = err
+				continue
+			}
+		}
+		pending = append(pending, prop)
+	}
+	attempts, backoff := 1, time.Duration(0)
+	if gpu.retry != nil {
+		attempts, backoff = gpu.retry.Attempts, gpu.retry.InitialBackoff
+	}
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		cprops := make([]C.int, len(pending))
+		for i, prop := range pending {
+			cprops[i] = C.int(prop)
+		}
+		cvalues := make([]C.ulonglong, len(pending))
+		cresults := make([]C.int, len(pending))
+
+		C.bridge_query_many(gpu.handle(), &cprops[0], C.uint(len(pending)), &cvalues[0], &cresults[0])
+
+		var retry []Property
+		for i, prop := range pending {
+			if cresults[i] == C.NVML_SUCCESS {
+				values[prop] = Value(cvalues[i])
+				delete(errs, prop)
+				continue
+			}
+
+			function, ok := propertyFunctions[prop]
+			if !ok {
+				function = "bridge_query_many"
+			}
+			err := newNVMLError(function, C.nvmlReturn_t(cresults[i]))
+			errs[prop] = err
+			if IsRetryable(err) {
+				retry = append(retry, prop)
+			}
+		}
+
+		if attempt >= attempts || len(retry) == 0 {
+			break
+		}
+		pending = retry
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > gpu.retry.MaxBackoff {
+			backoff = gpu.retry.MaxBackoff
+		}
+	}
+
+	var joined []error
+	for _, prop := range props {
+		if err, ok := errs[prop]; ok {
+			joined = append(joined, err)
+		}
+	}
+
+	return values, errors.Join(joined...)
+}
@@ -0,0 +1,77 @@
+package nvml
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"time"
+)
+
+// RetryPolicy is how often and how patiently to retry NVML calls that failed
+// with a transient error
+type RetryPolicy struct {
+	// Attempts is the total number of calls, including the first
+	Attempts int
+	// InitialBackoff is the delay before the first retry; it doubles with
+	// each further retry, up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy makes up to three attempts over about 30ms
+var DefaultRetryPolicy = RetryPolicy{Attempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: time.Second}
+
+// retryableCodes are the NVML return codes that are worth retrying, as they
+// are typically caused by contention in the driver
+var retryableCodes = map[int]bool{
+	C.NVML_ERROR_UNKNOWN: true,
+	C.NVML_ERROR_TIMEOUT: true,
+	C.NVML_ERROR_IN_USE:  true,
+}
+
+// IsRetryable reports whether err is an *NVMLError with a transient code:
+// NVML_ERROR_UNKNOWN, NVML_ERROR_TIMEOUT or NVML_ERROR_IN_USE
+func IsRetryable(err error) bool {
+	var nvmlerr *NVMLError
+	return errors.As(err, &nvmlerr) && retryableCodes[nvmlerr.Code]
+}
+
+// Retry calls f until it succeeds, fails with an error that isn't retryable,
+// or the policy's attempts are used up, and returns its last result
+func Retry[T any](policy RetryPolicy, f func() (T, error)) (T, error) {
+	backoff := policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		value, err := f()
+		if err == nil || attempt >= policy.Attempts || !IsRetryable(err) {
+			return value, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// WithRetry makes the device retry transient failures according to policy.
+// It applies to fetching the static properties, the integer and text property
+// getters, and QueryMany.
+func WithRetry(policy RetryPolicy) DeviceOption {
+	return func(o *deviceOptions) {
+		o.retry = &policy
+	}
+}
+
+// withRetry calls f, retrying it according to the device's policy, if any
+func withRetry[T any](gpu *Device, f func() (T, error)) (T, error) {
+	if gpu.retry == nil {
+		return f()
+	}
+
+	return Retry(*gpu.retry, f)
+}
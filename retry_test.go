@@ -0,0 +1,43 @@
+package nvml
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRetry(t *testing.T) {
+	var transient *NVMLError
+	for code := range retryableCodes {
+		transient = &NVMLError{Function: "test", Code: code}
+		break
+	}
+	permanent := errors.New("permanent")
+	policy := RetryPolicy{Attempts: 3}
+
+	var tests = []struct {
+		name     string
+		failures []error
+		calls    int
+		err      error
+	}{
+		{"success", nil, 1, nil},
+		{"transient once", []error{transient}, 2, nil},
+		{"transient always", []error{transient, transient, transient, transient}, 3, transient},
+		{"permanent", []error{permanent}, 1, permanent},
+	}
+
+	for _, ts := range tests {
+		calls := 0
+		_, err := Retry(policy, func() (int, error) {
+			calls++
+			if calls <= len(ts.failures) {
+				return 0, ts.failures[calls-1]
+			}
+			return 1, nil
+		})
+
+		if calls != ts.calls || err != ts.err {
+			t.Errorf("%s: %d calls, error %v, expected %d calls, error %v", ts.name, calls, err, ts.calls, ts.err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Code generated by nvmlgen from nvml.h; DO NOT EDIT.
+
+package nvml
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+// Return is an nvmlReturn_t, the result of an NVML function
+type Return int
+
+const (
+	ReturnSuccess              Return = C.NVML_SUCCESS
+	ReturnUninitialized        Return = C.NVML_ERROR_UNINITIALIZED
+	ReturnInvalidArgument      Return = C.NVML_ERROR_INVALID_ARGUMENT
+	ReturnNotSupported         Return = C.NVML_ERROR_NOT_SUPPORTED
+	ReturnNoPermission         Return = C.NVML_ERROR_NO_PERMISSION
+	ReturnAlreadyInitialized   Return = C.NVML_ERROR_ALREADY_INITIALIZED
+	ReturnNotFound             Return = C.NVML_ERROR_NOT_FOUND
+	ReturnInsufficientSize     Return = C.NVML_ERROR_INSUFFICIENT_SIZE
+	ReturnInsufficientPower    Return = C.NVML_ERROR_INSUFFICIENT_POWER
+	ReturnDriverNotLoaded      Return = C.NVML_ERROR_DRIVER_NOT_LOADED
+	ReturnTimeout              Return = C.NVML_ERROR_TIMEOUT
+	ReturnIrqIssue             Return = C.NVML_ERROR_IRQ_ISSUE
+	ReturnLibraryNotFound      Return = C.NVML_ERROR_LIBRARY_NOT_FOUND
+	ReturnFunctionNotFound     Return = C.NVML_ERROR_FUNCTION_NOT_FOUND
+	ReturnCorruptedInforom     Return = C.NVML_ERROR_CORRUPTED_INFOROM
+	ReturnGpuIsLost            Return = C.NVML_ERROR_GPU_IS_LOST
+	ReturnResetRequired        Return = C.NVML_ERROR_RESET_REQUIRED
+	ReturnOperatingSystem      Return = C.NVML_ERROR_OPERATING_SYSTEM
+	ReturnLibRmVersionMismatch Return = C.NVML_ERROR_LIB_RM_VERSION_MISMATCH
+	ReturnInUse                Return = C.NVML_ERROR_IN_USE
+	ReturnNoData               Return = C.NVML_ERROR_NO_DATA
+	ReturnUnknown              Return = C.NVML_ERROR_UNKNOWN
+)
+
+var returns = []Return{
+	ReturnSuccess,
+	ReturnUninitialized,
+	ReturnInvalidArgument,
+	ReturnNotSupported,
+	ReturnNoPermission,
+	ReturnAlreadyInitialized,
+	ReturnNotFound,
+	ReturnInsufficientSize,
+	ReturnInsufficientPower,
+	ReturnDriverNotLoaded,
+	ReturnTimeout,
+	ReturnIrqIssue,
+	ReturnLibraryNotFound,
+	ReturnFunctionNotFound,
+	ReturnCorruptedInforom,
+	ReturnGpuIsLost,
+	ReturnResetRequired,
+	ReturnOperatingSystem,
+	ReturnLibRmVersionMismatch,
+	ReturnInUse,
+	ReturnNoData,
+	ReturnUnknown,
+}
+
+func (r Return) String() string {
+	switch r {
+	case ReturnSuccess:
+		return "Success"
+	case ReturnUninitialized:
+		return "Uninitialized"
+	case ReturnInvalidArgument:
+		return "InvalidArgument"
+	case ReturnNotSupported:
+		return "NotSupported"
+	case ReturnNoPermission:
+		return "NoPermission"
+	case ReturnAlreadyInitialized:
+		return "AlreadyInitialized"
+	case ReturnNotFound:
+		return "NotFound"
+	case ReturnInsufficientSize:
+		return "InsufficientSize"
+	case ReturnInsufficientPower:
+		return "InsufficientPower"
+	case ReturnDriverNotLoaded:
+		return "DriverNotLoaded"
+	case ReturnTimeout:
+		return "Timeout"
+	case ReturnIrqIssue:
+		return "IrqIssue"
+	case ReturnLibraryNotFound:
+		return "LibraryNotFound"
+	case ReturnFunctionNotFound:
+		return "FunctionNotFound"
+	case ReturnCorruptedInforom:
+		return "CorruptedInforom"
+	case ReturnGpuIsLost:
+		return "GpuIsLost"
+	case ReturnResetRequired:
+		return "ResetRequired"
+	case ReturnOperatingSystem:
+		return "OperatingSystem"
+	case ReturnLibRmVersionMismatch:
+		return "LibRmVersionMismatch"
+	case ReturnInUse:
+		return "InUse"
+	case ReturnNoData:
+		return "NoData"
+	case ReturnUnknown:
+		return "Unknown"
+	}
+
+	return fmt.Sprintf("Return(%d)", int(r))
+}
@@ -0,0 +1,127 @@
+package nvml
+
+// See https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// SamplingType is a metric the driver keeps a buffer of recent samples of
+type SamplingType int
+
+const (
+	// SamplesTotalPower is the total power drawn by the GPU, in mW
+	SamplesTotalPower SamplingType = C.NVML_TOTAL_POWER_SAMPLES
+	// SamplesGPUUtilization is the percent of time one or more kernels were
+	// executing on the GPU
+	SamplesGPUUtilization SamplingType = C.NVML_GPU_UTILIZATION_SAMPLES
+	// SamplesMemoryUtilization is the percent of time device memory was being
+	// read or written
+	SamplesMemoryUtilization SamplingType = C.NVML_MEMORY_UTILIZATION_SAMPLES
+	// SamplesEncoderUtilization is the percent of time NVENC was busy
+	SamplesEncoderUtilization SamplingType = C.NVML_ENC_UTILIZATION_SAMPLES
+	// SamplesDecoderUtilization is the percent of time NVDEC was busy
+	SamplesDecoderUtilization SamplingType = C.NVML_DEC_UTILIZATION_SAMPLES
+	// SamplesProcessorClock is the processor clock, in MHz
+	SamplesProcessorClock SamplingType = C.NVML_PROCESSOR_CLK_SAMPLES
+	// SamplesMemoryClock is the memory clock, in MHz
+	SamplesMemoryClock SamplingType = C.NVML_MEMORY_CLK_SAMPLES
+)
+
+func (t SamplingType) String() string {
+	switch t {
+	case SamplesTotalPower:
+		return "TotalPower"
+	case SamplesGPUUtilization:
+		return "GPUUtilization"
+	case SamplesMemoryUtilization:
+		return "MemoryUtilization"
+	case SamplesEncoderUtilization:
+		return "EncoderUtilization"
+	case SamplesDecoderUtilization:
+		return "DecoderUtilization"
+	case SamplesProcessorClock:
+		return "ProcessorClock"
+	case SamplesMemoryClock:
+		return "MemoryClock"
+	}
+
+	return fmt.Sprintf("SamplingType(%d)", int(t))
+}
+
+// Sample is a single sample from the driver's buffer. TimeStamp is the CPU
+// timestamp in microseconds; pass the newest one seen to Samples to only get
+// samples taken since.
+type Sample struct {
+	TimeStamp uint64
+	Value     uint64
+}
+
+// Time returns the time the sample was taken
+func (s Sample) Time() time.Time {
+	return time.Unix(0, int64(s.TimeStamp)*int64(time.Microsecond))
+}
+
+// sampleValue converts a C.nvmlValue_t union of the given type to a uint64.
+// Power, utilization and clock samples are all unsigned ints in practice.
+func sampleValue(cvalue *C.nvmlValue_t, cvaltype C.nvmlValueType_t) uint64 {
+	p := unsafe.Pointer(cvalue)
+
+	switch cvaltype {
+	case C.NVML_VALUE_TYPE_DOUBLE:
+		return uint64(*(*C.double)(p))
+	case C.NVML_VALUE_TYPE_UNSIGNED_INT:
+		return uint64(*(*C.uint)(p))
+	case C.NVML_VALUE_TYPE_UNSIGNED_LONG:
+		return uint64(*(*C.ulong)(p))
+	}
+
+	return uint64(*(*C.ulonglong)(p))
+}
+
+// Samples returns the samples of the given type that the driver has buffered
+// since lastSeenTimeStamp, or all buffered samples if it is 0. Polling this at
+// a coarse interval doesn't miss the spikes that single readings would.
+func (gpu *Device) Samples(sampleType SamplingType, lastSeenTimeStamp uint64) ([]Sample, error) {
+	var result C.nvmlReturn_t
+	var cvaltype C.nvmlValueType_t
+	var ccount C.uint
+	var samples []Sample
+
+	if err := requireFunction("nvmlDeviceGetSamples"); err != nil {
+		return samples, err
+	}
+
+	result = C.nvmlDeviceGetSamples(gpu.handle(), C.nvmlSamplingType_t(sampleType), C.ulonglong(lastSeenTimeStamp), &cvaltype, &ccount, nil)
+	if result == C.NVML_ERROR_NOT_FOUND || (result == C.NVML_SUCCESS && ccount == 0) {
+		return samples, nil
+	}
+	if result != C.NVML_SUCCESS {
+		return samples, newNVMLError("nvmlDeviceGetSamples", result)
+	}
+
+	csamples := make([]C.nvmlSample_t, ccount)
+	result = C.nvmlDeviceGetSamples(gpu.handle(), C.nvmlSamplingType_t(sampleType), C.ulonglong(lastSeenTimeStamp), &cvaltype, &ccount, &csamples[0])
+	if result == C.NVML_ERROR_NOT_FOUND {
+		return samples, nil
+	}
+	if result != C.NVML_SUCCESS {
+		return samples, newNVMLError("nvmlDeviceGetSamples", result)
+	}
+
+	for i := range csamples[:ccount] {
+		samples = append(samples, Sample{
+			TimeStamp: uint64(csamples[i].timeStamp),
+			Value:     sampleValue(&csamples[i].sampleValue, cvaltype),
+		})
+	}
+
+	return samples, nil
+}
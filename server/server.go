@@ -0,0 +1,249 @@
+// Package server serves NVML device data over gRPC, with a REST gateway, so
+// that non-Go and remote consumers can read it from a single privileged
+// process per host.
+//
+// The service is defined in serverpb/nvml.proto. Server implements it, and
+// NewGateway serves its HTTP rules as REST endpoints by forwarding them to a
+// connection to the service:
+//
+//	GET /devices                       the devices, with their identification
+//	GET /devices/{index}/snapshot      a snapshot of one device
+//	GET /snapshots                     snapshots of all devices
+//	GET /devices/{index}/stream        newline-delimited JSON readings, each
+//	                                   wrapped in {"result": ...}, every
+//	                                   ?interval= (default 1s), until the
+//	                                   client disconnects
+//
+// JSON field names are those of the proto fields, e.g. pci_bus_id, and
+// metrics a device doesn't support are left out of its snapshot. Failed calls
+// return the gateway's JSON error with the HTTP status of the gRPC code, e.g.
+// 404 for a device that doesn't exist.
+//
+// After changing nvml.proto, regenerate the Go code with go generate, which
+// needs protoc, protoc-gen-go, protoc-gen-go-grpc, protoc-gen-grpc-gateway and
+// the googleapis protos on the include path.
+package server
+
+//go:generate protoc -I serverpb -I ${GOOGLEAPIS} --go_out=serverpb --go_opt=paths=source_relative --go-grpc_out=serverpb --go-grpc_opt=paths=source_relative --grpc-gateway_out=serverpb --grpc-gateway_opt=paths=source_relative nvml.proto
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	nvml "github.com/davidr/go-nvml"
+	"github.com/davidr/go-nvml/server/serverpb"
+)
+
+// Server implements the NVML gRPC service for a fixed set of devices
+type Server struct {
+	serverpb.UnimplementedNVMLServer
+
+	devices []*nvml.Device
+}
+
+// New returns a Server for the given devices. NVML must stay initialized while
+// it is serving.
+func New(devices []*nvml.Device) *Server {
+	return &Server{devices: devices}
+}
+
+// Register registers the service with a gRPC server
+func (s *Server) Register(g *grpc.Server) {
+	serverpb.RegisterNVMLServer(g, s)
+}
+
+// NewGateway returns an http.Handler serving the REST endpoints of the service
+// by forwarding them over conn. HEAD requests are served as GET.
+func NewGateway(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+		}),
+		runtime.WithRoutingErrorHandler(routingError),
+	)
+
+	if err := serverpb.RegisterNVMLHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			r = r.Clone(r.Context())
+			r.Method = http.MethodGet
+		}
+		mux.ServeHTTP(w, r)
+	}), nil
+}
+
+// routingError answers requests with a method other than GET and HEAD with a
+// 405 rather than the gateway's 501
+func routingError(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, httpStatus int) {
+	if httpStatus == http.StatusMethodNotAllowed {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	runtime.DefaultRoutingErrorHandler(ctx, mux, marshaler, w, r, httpStatus)
+}
+
+// device returns the device with the given NVML index
+func (s *Server) device(index uint32) (*nvml.Device, error) {
+	for _, gpu := range s.devices {
+		if i, err := gpu.Index(); err == nil && i == uint(index) {
+			return gpu, nil
+		}
+	}
+
+	return nil, status.Error(codes.NotFound, "no such device")
+}
+
+// ListDevices implements serverpb.NVMLServer
+func (s *Server) ListDevices(ctx context.Context, req *serverpb.ListDevicesRequest) (*serverpb.ListDevicesResponse, error) {
+	resp := &serverpb.ListDevicesResponse{}
+
+	for _, gpu := range s.devices {
+		d := &serverpb.Device{}
+		if index, err := gpu.Index(); err == nil {
+			d.Index = uint32(index)
+		}
+		d.Uuid, _ = gpu.UUID()
+		d.Name, _ = gpu.Name()
+		if pciinfo, err := gpu.PciInfo(); err == nil {
+			d.PciBusId = pciinfo.BusID
+		}
+		resp.Devices = append(resp.Devices, d)
+	}
+
+	return resp, nil
+}
+
+// GetSnapshot implements serverpb.NVMLServer
+func (s *Server) GetSnapshot(ctx context.Context, req *serverpb.GetSnapshotRequest) (*serverpb.Snapshot, error) {
+	gpu, err := s.device(req.GetIndex())
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := gpu.Snapshot()
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return snapshotProto(snapshot), nil
+}
+
+// ListSnapshots implements serverpb.NVMLServer. Devices that can't be read
+// are left out.
+func (s *Server) ListSnapshots(ctx context.Context, req *serverpb.ListSnapshotsRequest) (*serverpb.ListSnapshotsResponse, error) {
+	resp := &serverpb.ListSnapshotsResponse{}
+
+	for _, gpu := range s.devices {
+		if snapshot, err := gpu.Snapshot(); err == nil {
+			resp.Snapshots = append(resp.Snapshots, snapshotProto(snapshot))
+		}
+	}
+
+	return resp, nil
+}
+
+// StreamReadings implements serverpb.NVMLServer
+func (s *Server) StreamReadings(req *serverpb.StreamReadingsRequest, stream serverpb.NVML_StreamReadingsServer) error {
+	gpu, err := s.device(req.GetIndex())
+	if err != nil {
+		return err
+	}
+
+	interval := time.Second
+	if req.Interval != nil {
+		if err := req.Interval.CheckValid(); err != nil || req.Interval.AsDuration() <= 0 {
+			return status.Error(codes.InvalidArgument, "invalid interval")
+		}
+		interval = req.Interval.AsDuration()
+	}
+
+	uuid, _ := gpu.UUID()
+	monitor := nvml.NewMonitor([]*nvml.Device{gpu}, interval)
+	monitor.Start()
+	defer monitor.Stop()
+
+	// Drain errors so the monitor never blocks on them
+	go func() {
+		for range monitor.Errors() {
+		}
+	}()
+
+	for {
+		select {
+		case reading := <-monitor.Readings():
+			err := stream.Send(&serverpb.Reading{
+				Uuid:     uuid,
+				Property: reading.Property.String(),
+				Value:    uint64(reading.Value),
+				Time:     timestamppb.New(reading.Time),
+			})
+			if err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// optionalUint32 converts an optional metric of a snapshot
+func optionalUint32(v *uint) *uint32 {
+	if v == nil {
+		return nil
+	}
+
+	u := uint32(*v)
+	return &u
+}
+
+// snapshotProto converts a snapshot to its message
+func snapshotProto(snapshot nvml.DeviceSnapshot) *serverpb.Snapshot {
+	msg := &serverpb.Snapshot{
+		Time:              timestamppb.New(snapshot.Time),
+		Uuid:              snapshot.UUID,
+		Name:              snapshot.Name,
+		Serial:            snapshot.Serial,
+		Index:             uint32(snapshot.Index),
+		PciBusId:          snapshot.PciBusID,
+		Temperature:       optionalUint32(snapshot.Temperature),
+		FanSpeed:          optionalUint32(snapshot.FanSpeed),
+		PowerUsage:        optionalUint32(snapshot.PowerUsage),
+		PowerLimit:        optionalUint32(snapshot.PowerLimit),
+		ClockGraphics:     optionalUint32(snapshot.ClockGraphics),
+		ClockSm:           optionalUint32(snapshot.ClockSM),
+		ClockMem:          optionalUint32(snapshot.ClockMem),
+		ClockVideo:        optionalUint32(snapshot.ClockVideo),
+		MemoryTotal:       snapshot.MemoryTotal,
+		MemoryUsed:        snapshot.MemoryUsed,
+		MemoryFree:        snapshot.MemoryFree,
+		UtilizationGpu:    optionalUint32(snapshot.UtilizationGPU),
+		UtilizationMemory: optionalUint32(snapshot.UtilizationMemory),
+	}
+
+	if snapshot.PerformanceState != nil {
+		pstate := uint32(*snapshot.PerformanceState)
+		msg.PerformanceState = &pstate
+	}
+
+	for _, process := range snapshot.Processes {
+		msg.Processes = append(msg.Processes, &serverpb.Process{
+			Pid:           uint32(process.PID),
+			UsedGpuMemory: process.UsedGpuMemory,
+		})
+	}
+
+	return msg
+}
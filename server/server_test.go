@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/davidr/go-nvml/server/serverpb"
+)
+
+// dial serves s on an in-memory listener and returns a connection to it
+func dial(t *testing.T, s *Server) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	s.Register(g)
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestService(t *testing.T) {
+	client := serverpb.NewNVMLClient(dial(t, New(nil)))
+	ctx := context.Background()
+
+	devices, err := client.ListDevices(ctx, &serverpb.ListDevicesRequest{})
+	if err != nil || len(devices.Devices) != 0 {
+		t.Errorf("ListDevices = %v, %v, expected no devices", devices, err)
+	}
+
+	_, err = client.GetSnapshot(ctx, &serverpb.GetSnapshotRequest{Index: 0})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetSnapshot of a missing device returned %v, expected NotFound", err)
+	}
+
+	stream, err := client.StreamReadings(ctx, &serverpb.StreamReadingsRequest{Index: 0})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("StreamReadings of a missing device returned %v, expected NotFound", err)
+	}
+}
+
+func TestGatewayRoutes(t *testing.T) {
+	gateway, err := NewGateway(context.Background(), dial(t, New(nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{"GET", "/devices", http.StatusOK, "[]"},
+		{"HEAD", "/devices", http.StatusOK, ""},
+		{"GET", "/snapshots", http.StatusOK, "[]"},
+		{"GET", "/devices/0/snapshot", http.StatusNotFound, "no such device"},
+		{"GET", "/devices/0/stream", http.StatusNotFound, "no such device"},
+		{"GET", "/devices/x/snapshot", http.StatusBadRequest, ""},
+		{"GET", "/devices/0/bogus", http.StatusNotFound, ""},
+		{"GET", "/devices/0/snapshot/extra", http.StatusNotFound, ""},
+		{"GET", "/", http.StatusNotFound, ""},
+		{"POST", "/devices", http.StatusMethodNotAllowed, ""},
+		{"DELETE", "/devices/0/snapshot", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, ts := range tests {
+		w := httptest.NewRecorder()
+		gateway.ServeHTTP(w, httptest.NewRequest(ts.method, ts.path, nil))
+
+		if w.Code != ts.code || !strings.Contains(w.Body.String(), ts.body) {
+			t.Errorf("%s %s = %d %q, expected %d containing %q", ts.method, ts.path, w.Code, w.Body.String(), ts.code, ts.body)
+		}
+	}
+}
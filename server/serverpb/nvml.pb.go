@@ -0,0 +1,836 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: nvml.proto
+
+package serverpb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Device struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Uuid          string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	PciBusId      string                 `protobuf:"bytes,4,opt,name=pci_bus_id,json=pciBusId,proto3" json:"pci_bus_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_nvml_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_nvml_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_nvml_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Device) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Device) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Device) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Device) GetPciBusId() string {
+	if x != nil {
+		return x.PciBusId
+	}
+	return ""
+}
+
+type ListDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesRequest) Reset() {
+	*x = ListDevicesRequest{}
+	mi := &file_nvml_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesRequest) ProtoMessage() {}
+
+func (x *ListDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nvml_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_nvml_proto_rawDescGZIP(), []int{1}
+}
+
+type ListDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesResponse) Reset() {
+	*x = ListDevicesResponse{}
+	mi := &file_nvml_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesResponse) ProtoMessage() {}
+
+func (x *ListDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nvml_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_nvml_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListDevicesResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type GetSnapshotRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// NVML index of the device
+	Index         uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSnapshotRequest) Reset() {
+	*x = GetSnapshotRequest{}
+	mi := &file_nvml_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSnapshotRequest) ProtoMessage() {}
+
+func (x *GetSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nvml_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*GetSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_nvml_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSnapshotRequest) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+type ListSnapshotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSnapshotsRequest) Reset() {
+	*x = ListSnapshotsRequest{}
+	mi := &file_nvml_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSnapshotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSnapshotsRequest) ProtoMessage() {}
+
+func (x *ListSnapshotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nvml_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSnapshotsRequest.ProtoReflect.Descriptor instead.
+func (*ListSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return file_nvml_proto_rawDescGZIP(), []int{4}
+}
+
+type ListSnapshotsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Snapshots     []*Snapshot            `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSnapshotsResponse) Reset() {
+	*x = ListSnapshotsResponse{}
+	mi := &file_nvml_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSnapshotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSnapshotsResponse) ProtoMessage() {}
+
+func (x *ListSnapshotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_nvml_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSnapshotsResponse.ProtoReflect.Descriptor instead.
+func (*ListSnapshotsResponse) Descriptor() ([]byte, []int) {
+	return file_nvml_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSnapshotsResponse) GetSnapshots() []*Snapshot {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+type Process struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pid           uint32                 `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	UsedGpuMemory uint64                 `protobuf:"varint,2,opt,name=used_gpu_memory,json=usedGpuMemory,proto3" json:"used_gpu_memory,omitempty"` // bytes
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Process) Reset() {
+	*x = Process{}
+	mi := &file_nvml_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Process) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Process) ProtoMessage() {}
+
+func (x *Process) ProtoReflect() protoreflect.Message {
+	mi := &file_nvml_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Process.ProtoReflect.Descriptor instead.
+func (*Process) Descriptor() ([]byte, []int) {
+	return file_nvml_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Process) GetPid() uint32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *Process) GetUsedGpuMemory() uint64 {
+	if x != nil {
+		return x.UsedGpuMemory
+	}
+	return 0
+}
+
+// Snapshot is the state of a device at one point in time. Metrics the device
+// doesn't support are unset.
+type Snapshot struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Time              *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Uuid              string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name              string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Serial            string                 `protobuf:"bytes,4,opt,name=serial,proto3" json:"serial,omitempty"`
+	Index             uint32                 `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
+	PciBusId          string                 `protobuf:"bytes,6,opt,name=pci_bus_id,json=pciBusId,proto3" json:"pci_bus_id,omitempty"`
+	PerformanceState  *uint32                `protobuf:"varint,7,opt,name=performance_state,json=performanceState,proto3,oneof" json:"performance_state,omitempty"`
+	Temperature       *uint32                `protobuf:"varint,8,opt,name=temperature,proto3,oneof" json:"temperature,omitempty"`                                       // °C
+	FanSpeed          *uint32                `protobuf:"varint,9,opt,name=fan_speed,json=fanSpeed,proto3,oneof" json:"fan_speed,omitempty"`                             // %
+	PowerUsage        *uint32                `protobuf:"varint,10,opt,name=power_usage,json=powerUsage,proto3,oneof" json:"power_usage,omitempty"`                      // mW
+	PowerLimit        *uint32                `protobuf:"varint,11,opt,name=power_limit,json=powerLimit,proto3,oneof" json:"power_limit,omitempty"`                      // mW
+	ClockGraphics     *uint32                `protobuf:"varint,12,opt,name=clock_graphics,json=clockGraphics,proto3,oneof" json:"clock_graphics,omitempty"`             // MHz
+	ClockSm           *uint32                `protobuf:"varint,13,opt,name=clock_sm,json=clockSm,proto3,oneof" json:"clock_sm,omitempty"`                               // MHz
+	ClockMem          *uint32                `protobuf:"varint,14,opt,name=clock_mem,json=clockMem,proto3,oneof" json:"clock_mem,omitempty"`                            // MHz
+	ClockVideo        *uint32                `protobuf:"varint,15,opt,name=clock_video,json=clockVideo,proto3,oneof" json:"clock_video,omitempty"`                      // MHz
+	MemoryTotal       *uint64                `protobuf:"varint,16,opt,name=memory_total,json=memoryTotal,proto3,oneof" json:"memory_total,omitempty"`                   // bytes
+	MemoryUsed        *uint64                `protobuf:"varint,17,opt,name=memory_used,json=memoryUsed,proto3,oneof" json:"memory_used,omitempty"`                      // bytes
+	MemoryFree        *uint64                `protobuf:"varint,18,opt,name=memory_free,json=memoryFree,proto3,oneof" json:"memory_free,omitempty"`                      // bytes
+	UtilizationGpu    *uint32                `protobuf:"varint,19,opt,name=utilization_gpu,json=utilizationGpu,proto3,oneof" json:"utilization_gpu,omitempty"`          // %
+	UtilizationMemory *uint32                `protobuf:"varint,20,opt,name=utilization_memory,json=utilizationMemory,proto3,oneof" json:"utilization_memory,omitempty"` // %
+	Processes         []*Process             `protobuf:"bytes,21,rep,name=processes,proto3" json:"processes,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	mi := &file_nvml_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_nvml_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_nvml_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Snapshot) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Snapshot) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Snapshot) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Snapshot) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+func (x *Snapshot) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Snapshot) GetPciBusId() string {
+	if x != nil {
+		return x.PciBusId
+	}
+	return ""
+}
+
+func (x *Snapshot) GetPerformanceState() uint32 {
+	if x != nil && x.PerformanceState != nil {
+		return *x.PerformanceState
+	}
+	return 0
+}
+
+func (x *Snapshot) GetTemperature() uint32 {
+	if x != nil && x.Temperature != nil {
+		return *x.Temperature
+	}
+	return 0
+}
+
+func (x *Snapshot) GetFanSpeed() uint32 {
+	if x != nil && x.FanSpeed != nil {
+		return *x.FanSpeed
+	}
+	return 0
+}
+
+func (x *Snapshot) GetPowerUsage() uint32 {
+	if x != nil && x.PowerUsage != nil {
+		return *x.PowerUsage
+	}
+	return 0
+}
+
+func (x *Snapshot) GetPowerLimit() uint32 {
+	if x != nil && x.PowerLimit != nil {
+		return *x.PowerLimit
+	}
+	return 0
+}
+
+func (x *Snapshot) GetClockGraphics() uint32 {
+	if x != nil && x.ClockGraphics != nil {
+		return *x.ClockGraphics
+	}
+	return 0
+}
+
+func (x *Snapshot) GetClockSm() uint32 {
+	if x != nil && x.ClockSm != nil {
+		return *x.ClockSm
+	}
+	return 0
+}
+
+func (x *Snapshot) GetClockMem() uint32 {
+	if x != nil && x.ClockMem != nil {
+		return *x.ClockMem
+	}
+	return 0
+}
+
+func (x *Snapshot) GetClockVideo() uint32 {
+	if x != nil && x.ClockVideo != nil {
+		return *x.ClockVideo
+	}
+	return 0
+}
+
+func (x *Snapshot) GetMemoryTotal() uint64 {
+	if x != nil && x.MemoryTotal != nil {
+		return *x.MemoryTotal
+	}
+	return 0
+}
+
+func (x *Snapshot) GetMemoryUsed() uint64 {
+	if x != nil && x.MemoryUsed != nil {
+		return *x.MemoryUsed
+	}
+	return 0
+}
+
+func (x *Snapshot) GetMemoryFree() uint64 {
+	if x != nil && x.MemoryFree != nil {
+		return *x.MemoryFree
+	}
+	return 0
+}
+
+func (x *Snapshot) GetUtilizationGpu() uint32 {
+	if x != nil && x.UtilizationGpu != nil {
+		return *x.UtilizationGpu
+	}
+	return 0
+}
+
+func (x *Snapshot) GetUtilizationMemory() uint32 {
+	if x != nil && x.UtilizationMemory != nil {
+		return *x.UtilizationMemory
+	}
+	return 0
+}
+
+func (x *Snapshot) GetProcesses() []*Process {
+	if x != nil {
+		return x.Processes
+	}
+	return nil
+}
+
+type StreamReadingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// NVML index of the device
+	Index uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Time between readings, 1s if unset
+	Interval      *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamReadingsRequest) Reset() {
+	*x = StreamReadingsRequest{}
+	mi := &file_nvml_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamReadingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamReadingsRequest) ProtoMessage() {}
+
+func (x *StreamReadingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_nvml_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamReadingsRequest.ProtoReflect.Descriptor instead.
+func (*StreamReadingsRequest) Descriptor() ([]byte, []int) {
+	return file_nvml_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StreamReadingsRequest) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *StreamReadingsRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+type Reading struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Property      string                 `protobuf:"bytes,2,opt,name=property,proto3" json:"property,omitempty"`
+	Value         uint64                 `protobuf:"varint,3,opt,name=value,proto3" json:"value,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reading) Reset() {
+	*x = Reading{}
+	mi := &file_nvml_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reading) ProtoMessage() {}
+
+func (x *Reading) ProtoReflect() protoreflect.Message {
+	mi := &file_nvml_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reading.ProtoReflect.Descriptor instead.
+func (*Reading) Descriptor() ([]byte, []int) {
+	return file_nvml_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Reading) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *Reading) GetProperty() string {
+	if x != nil {
+		return x.Property
+	}
+	return ""
+}
+
+func (x *Reading) GetValue() uint64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Reading) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+var File_nvml_proto protoreflect.FileDescriptor
+
+const file_nvml_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"nvml.proto\x12\x10gonvml.server.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"d\n" +
+	"\x06Device\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1c\n" +
+	"\n" +
+	"pci_bus_id\x18\x04 \x01(\tR\bpciBusId\"\x14\n" +
+	"\x12ListDevicesRequest\"I\n" +
+	"\x13ListDevicesResponse\x122\n" +
+	"\adevices\x18\x01 \x03(\v2\x18.gonvml.server.v1.DeviceR\adevices\"*\n" +
+	"\x12GetSnapshotRequest\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\"\x16\n" +
+	"\x14ListSnapshotsRequest\"Q\n" +
+	"\x15ListSnapshotsResponse\x128\n" +
+	"\tsnapshots\x18\x01 \x03(\v2\x1a.gonvml.server.v1.SnapshotR\tsnapshots\"C\n" +
+	"\aProcess\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\rR\x03pid\x12&\n" +
+	"\x0fused_gpu_memory\x18\x02 \x01(\x04R\rusedGpuMemory\"\x86\b\n" +
+	"\bSnapshot\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06serial\x18\x04 \x01(\tR\x06serial\x12\x14\n" +
+	"\x05index\x18\x05 \x01(\rR\x05index\x12\x1c\n" +
+	"\n" +
+	"pci_bus_id\x18\x06 \x01(\tR\bpciBusId\x120\n" +
+	"\x11performance_state\x18\a \x01(\rH\x00R\x10performanceState\x88\x01\x01\x12%\n" +
+	"\vtemperature\x18\b \x01(\rH\x01R\vtemperature\x88\x01\x01\x12 \n" +
+	"\tfan_speed\x18\t \x01(\rH\x02R\bfanSpeed\x88\x01\x01\x12$\n" +
+	"\vpower_usage\x18\n" +
+	" \x01(\rH\x03R\n" +
+	"powerUsage\x88\x01\x01\x12$\n" +
+	"\vpower_limit\x18\v \x01(\rH\x04R\n" +
+	"powerLimit\x88\x01\x01\x12*\n" +
+	"\x0eclock_graphics\x18\f \x01(\rH\x05R\rclockGraphics\x88\x01\x01\x12\x1e\n" +
+	"\bclock_sm\x18\r \x01(\rH\x06R\aclockSm\x88\x01\x01\x12 \n" +
+	"\tclock_mem\x18\x0e \x01(\rH\aR\bclockMem\x88\x01\x01\x12$\n" +
+	"\vclock_video\x18\x0f \x01(\rH\bR\n" +
+	"clockVideo\x88\x01\x01\x12&\n" +
+	"\fmemory_total\x18\x10 \x01(\x04H\tR\vmemoryTotal\x88\x01\x01\x12$\n" +
+	"\vmemory_used\x18\x11 \x01(\x04H\n" +
+	"R\n" +
+	"memoryUsed\x88\x01\x01\x12$\n" +
+	"\vmemory_free\x18\x12 \x01(\x04H\vR\n" +
+	"memoryFree\x88\x01\x01\x12,\n" +
+	"\x0futilization_gpu\x18\x13 \x01(\rH\fR\x0eutilizationGpu\x88\x01\x01\x122\n" +
+	"\x12utilization_memory\x18\x14 \x01(\rH\rR\x11utilizationMemory\x88\x01\x01\x127\n" +
+	"\tprocesses\x18\x15 \x03(\v2\x19.gonvml.server.v1.ProcessR\tprocessesB\x14\n" +
+	"\x12_performance_stateB\x0e\n" +
+	"\f_temperatureB\f\n" +
+	"\n" +
+	"_fan_speedB\x0e\n" +
+	"\f_power_usageB\x0e\n" +
+	"\f_power_limitB\x11\n" +
+	"\x0f_clock_graphicsB\v\n" +
+	"\t_clock_smB\f\n" +
+	"\n" +
+	"_clock_memB\x0e\n" +
+	"\f_clock_videoB\x0f\n" +
+	"\r_memory_totalB\x0e\n" +
+	"\f_memory_usedB\x0e\n" +
+	"\f_memory_freeB\x12\n" +
+	"\x10_utilization_gpuB\x15\n" +
+	"\x13_utilization_memory\"d\n" +
+	"\x15StreamReadingsRequest\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\"\x7f\n" +
+	"\aReading\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x1a\n" +
+	"\bproperty\x18\x02 \x01(\tR\bproperty\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x04R\x05value\x12.\n" +
+	"\x04time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04time2\xeb\x03\n" +
+	"\x04NVML\x12u\n" +
+	"\vListDevices\x12$.gonvml.server.v1.ListDevicesRequest\x1a%.gonvml.server.v1.ListDevicesResponse\"\x19\x82\xd3\xe4\x93\x02\x13b\adevices\x12\b/devices\x12r\n" +
+	"\vGetSnapshot\x12$.gonvml.server.v1.GetSnapshotRequest\x1a\x1a.gonvml.server.v1.Snapshot\"!\x82\xd3\xe4\x93\x02\x1b\x12\x19/devices/{index}/snapshot\x12\x7f\n" +
+	"\rListSnapshots\x12&.gonvml.server.v1.ListSnapshotsRequest\x1a'.gonvml.server.v1.ListSnapshotsResponse\"\x1d\x82\xd3\xe4\x93\x02\x17b\tsnapshots\x12\n" +
+	"/snapshots\x12w\n" +
+	"\x0eStreamReadings\x12'.gonvml.server.v1.StreamReadingsRequest\x1a\x19.gonvml.server.v1.Reading\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/devices/{index}/stream0\x01B+Z)github.com/davidr/go-nvml/server/serverpbb\x06proto3"
+
+var (
+	file_nvml_proto_rawDescOnce sync.Once
+	file_nvml_proto_rawDescData []byte
+)
+
+func file_nvml_proto_rawDescGZIP() []byte {
+	file_nvml_proto_rawDescOnce.Do(func() {
+		file_nvml_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_nvml_proto_rawDesc), len(file_nvml_proto_rawDesc)))
+	})
+	return file_nvml_proto_rawDescData
+}
+
+var file_nvml_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_nvml_proto_goTypes = []any{
+	(*Device)(nil),                // 0: gonvml.server.v1.Device
+	(*ListDevicesRequest)(nil),    // 1: gonvml.server.v1.ListDevicesRequest
+	(*ListDevicesResponse)(nil),   // 2: gonvml.server.v1.ListDevicesResponse
+	(*GetSnapshotRequest)(nil),    // 3: gonvml.server.v1.GetSnapshotRequest
+	(*ListSnapshotsRequest)(nil),  // 4: gonvml.server.v1.ListSnapshotsRequest
+	(*ListSnapshotsResponse)(nil), // 5: gonvml.server.v1.ListSnapshotsResponse
+	(*Process)(nil),               // 6: gonvml.server.v1.Process
+	(*Snapshot)(nil),              // 7: gonvml.server.v1.Snapshot
+	(*StreamReadingsRequest)(nil), // 8: gonvml.server.v1.StreamReadingsRequest
+	(*Reading)(nil),               // 9: gonvml.server.v1.Reading
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 11: google.protobuf.Duration
+}
+var file_nvml_proto_depIdxs = []int32{
+	0,  // 0: gonvml.server.v1.ListDevicesResponse.devices:type_name -> gonvml.server.v1.Device
+	7,  // 1: gonvml.server.v1.ListSnapshotsResponse.snapshots:type_name -> gonvml.server.v1.Snapshot
+	10, // 2: gonvml.server.v1.Snapshot.time:type_name -> google.protobuf.Timestamp
+	6,  // 3: gonvml.server.v1.Snapshot.processes:type_name -> gonvml.server.v1.Process
+	11, // 4: gonvml.server.v1.StreamReadingsRequest.interval:type_name -> google.protobuf.Duration
+	10, // 5: gonvml.server.v1.Reading.time:type_name -> google.protobuf.Timestamp
+	1,  // 6: gonvml.server.v1.NVML.ListDevices:input_type -> gonvml.server.v1.ListDevicesRequest
+	3,  // 7: gonvml.server.v1.NVML.GetSnapshot:input_type -> gonvml.server.v1.GetSnapshotRequest
+	4,  // 8: gonvml.server.v1.NVML.ListSnapshots:input_type -> gonvml.server.v1.ListSnapshotsRequest
+	8,  // 9: gonvml.server.v1.NVML.StreamReadings:input_type -> gonvml.server.v1.StreamReadingsRequest
+	2,  // 10: gonvml.server.v1.NVML.ListDevices:output_type -> gonvml.server.v1.ListDevicesResponse
+	7,  // 11: gonvml.server.v1.NVML.GetSnapshot:output_type -> gonvml.server.v1.Snapshot
+	5,  // 12: gonvml.server.v1.NVML.ListSnapshots:output_type -> gonvml.server.v1.ListSnapshotsResponse
+	9,  // 13: gonvml.server.v1.NVML.StreamReadings:output_type -> gonvml.server.v1.Reading
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_nvml_proto_init() }
+func file_nvml_proto_init() {
+	if File_nvml_proto != nil {
+		return
+	}
+	file_nvml_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_nvml_proto_rawDesc), len(file_nvml_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_nvml_proto_goTypes,
+		DependencyIndexes: file_nvml_proto_depIdxs,
+		MessageInfos:      file_nvml_proto_msgTypes,
+	}.Build()
+	File_nvml_proto = out.File
+	file_nvml_proto_goTypes = nil
+	file_nvml_proto_depIdxs = nil
+}
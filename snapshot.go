@@ -0,0 +1,188 @@
+package nvml
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeviceSnapshot is the complete state of a device at one point in time.
+// Metrics the device doesn't support are nil.
+type DeviceSnapshot struct {
+	Time time.Time
+
+	UUID     string
+	Name     string
+	Serial   string
+	Index    uint
+	PciBusID string
+
+	PerformanceState *Pstate
+	Temperature      *uint // °C
+	FanSpeed         *uint // %
+	PowerUsage       *uint // mW
+	PowerLimit       *uint // mW
+
+	ClockGraphics *uint // MHz
+	ClockSM       *uint // MHz
+	ClockMem      *uint // MHz
+	ClockVideo    *uint // MHz
+
+	MemoryTotal *uint64 // bytes
+	MemoryUsed  *uint64 // bytes
+	MemoryFree  *uint64 // bytes
+
+	UtilizationGPU    *uint // %
+	UtilizationMemory *uint // %
+
+	Processes []ProcessInfo
+}
+
+// snapshotProperties are the metrics Snapshot fetches through QueryMany
+var snapshotProperties = []Property{
+	PropertyPerformanceState,
+	PropertyTemperature,
+	PropertyFanSpeed,
+	PropertyPowerUsage,
+	PropertyEnforcedPowerLimit,
+	PropertyClockGraphics,
+	PropertyClockSM,
+	PropertyClockMem,
+	PropertyClockVideo,
+	PropertyMemoryTotal,
+	PropertyMemoryUsed,
+	PropertyMemoryFree,
+	PropertyUtilizationGPU,
+	PropertyUtilizationMemory,
+}
+
+// Snapshot returns the current state of the device. It only fails if the
+// device can't be identified; unsupported metrics are left out.
+func (gpu *Device) Snapshot() (DeviceSnapshot, error) {
+	var s DeviceSnapshot
+
+	uuid, err := gpu.UUID()
+	if err != nil {
+		return s, err
+	}
+
+	s.Time = time.Now()
+	s.UUID = uuid
+	s.Name, _ = gpu.Name()
+	s.Serial, _ = gpu.Serial()
+	s.Index, _ = gpu.Index()
+	if pciinfo, err := gpu.PciInfo(); err == nil {
+		s.PciBusID = pciinfo.BusID
+	}
+
+	values, _ := gpu.QueryMany(snapshotProperties...)
+	uintValue := func(p Property) *uint {
+		if v, ok := values[p]; ok {
+			u := uint(v)
+			return &u
+		}
+		return nil
+	}
+	uint64Value := func(p Property) *uint64 {
+		if v, ok := values[p]; ok {
+			u := uint64(v)
+			return &u
+		}
+		return nil
+	}
+
+	if v, ok := values[PropertyPerformanceState]; ok {
+		pstate := Pstate(v)
+		s.PerformanceState = &pstate
+	}
+	s.Temperature = uintValue(PropertyTemperature)
+	s.FanSpeed = uintValue(PropertyFanSpeed)
+	s.PowerUsage = uintValue(PropertyPowerUsage)
+	s.PowerLimit = uintValue(PropertyEnforcedPowerLimit)
+	s.ClockGraphics = uintValue(PropertyClockGraphics)
+	s.ClockSM = uintValue(PropertyClockSM)
+	s.ClockMem = uintValue(PropertyClockMem)
+	s.ClockVideo = uintValue(PropertyClockVideo)
+	s.MemoryTotal = uint64Value(PropertyMemoryTotal)
+	s.MemoryUsed = uint64Value(PropertyMemoryUsed)
+	s.MemoryFree = uint64Value(PropertyMemoryFree)
+	s.UtilizationGPU = uintValue(PropertyUtilizationGPU)
+	s.UtilizationMemory = uintValue(PropertyUtilizationMemory)
+
+	if processes, err := gpu.ComputeRunningProcesses(); err == nil {
+		s.Processes = append(s.Processes, processes...)
+	}
+	if processes, err := gpu.GraphicsRunningProcesses(); err == nil {
+		s.Processes = append(s.Processes, processes...)
+	}
+
+	return s, nil
+}
+
+type processJSON struct {
+	PID           uint   `json:"pid"`
+	UsedGpuMemory uint64 `json:"used_gpu_memory_bytes"`
+}
+
+type snapshotJSON struct {
+	Time     time.Time `json:"time"`
+	UUID     string    `json:"uuid"`
+	Name     string    `json:"name"`
+	Serial   string    `json:"serial,omitempty"`
+	Index    uint      `json:"index"`
+	PciBusID string    `json:"pci_bus_id"`
+
+	PerformanceState string `json:"performance_state,omitempty"`
+	Temperature      *uint  `json:"temperature_celsius,omitempty"`
+	FanSpeed         *uint  `json:"fan_speed_percent,omitempty"`
+	PowerUsage       *uint  `json:"power_usage_milliwatts,omitempty"`
+	PowerLimit       *uint  `json:"power_limit_milliwatts,omitempty"`
+
+	ClockGraphics *uint `json:"clock_graphics_mhz,omitempty"`
+	ClockSM       *uint `json:"clock_sm_mhz,omitempty"`
+	ClockMem      *uint `json:"clock_mem_mhz,omitempty"`
+	ClockVideo    *uint `json:"clock_video_mhz,omitempty"`
+
+	MemoryTotal *uint64 `json:"memory_total_bytes,omitempty"`
+	MemoryUsed  *uint64 `json:"memory_used_bytes,omitempty"`
+	MemoryFree  *uint64 `json:"memory_free_bytes,omitempty"`
+
+	UtilizationGPU    *uint `json:"utilization_gpu_percent,omitempty"`
+	UtilizationMemory *uint `json:"utilization_memory_percent,omitempty"`
+
+	Processes []processJSON `json:"processes"`
+}
+
+// MarshalJSON encodes the snapshot with stable, snake_case field names that
+// carry their unit. Unsupported metrics are omitted.
+func (s DeviceSnapshot) MarshalJSON() ([]byte, error) {
+	out := snapshotJSON{
+		Time:              s.Time,
+		UUID:              s.UUID,
+		Name:              s.Name,
+		Serial:            s.Serial,
+		Index:             s.Index,
+		PciBusID:          s.PciBusID,
+		Temperature:       s.Temperature,
+		FanSpeed:          s.FanSpeed,
+		PowerUsage:        s.PowerUsage,
+		PowerLimit:        s.PowerLimit,
+		ClockGraphics:     s.ClockGraphics,
+		ClockSM:           s.ClockSM,
+		ClockMem:          s.ClockMem,
+		ClockVideo:        s.ClockVideo,
+		MemoryTotal:       s.MemoryTotal,
+		MemoryUsed:        s.MemoryUsed,
+		MemoryFree:        s.MemoryFree,
+		UtilizationGPU:    s.UtilizationGPU,
+		UtilizationMemory: s.UtilizationMemory,
+		Processes:         []processJSON{},
+	}
+	if s.PerformanceState != nil {
+		out.PerformanceState = s.PerformanceState.String()
+	}
+	for _, p := range s.Processes {
+		out.Processes = append(out.Processes, processJSON{PID: p.PID, UsedGpuMemory: p.UsedGpuMemory})
+	}
+
+	return json.Marshal(out)
+}
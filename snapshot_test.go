@@ -0,0 +1,33 @@
+package nvml
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDeviceSnapshotMarshalJSON(t *testing.T) {
+	pstate := P2
+	temp := uint(65)
+	s := DeviceSnapshot{
+		Time:             time.Unix(0, 0).UTC(),
+		UUID:             "GPU-1234",
+		Name:             "Tesla P100",
+		PciBusID:         "0000:04:00.0",
+		PerformanceState: &pstate,
+		Temperature:      &temp,
+		Processes:        []ProcessInfo{{PID: 42, UsedGpuMemory: 1024}},
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("json.Marshal: %s", err)
+	}
+
+	expected := `{"time":"1970-01-01T00:00:00Z","uuid":"GPU-1234","name":"Tesla P100","index":0,` +
+		`"pci_bus_id":"0000:04:00.0","performance_state":"P2","temperature_celsius":65,` +
+		`"processes":[{"pid":42,"used_gpu_memory_bytes":1024}]}`
+	if string(b) != expected {
+		t.Errorf("json.Marshal = %s, expected %s", b, expected)
+	}
+}
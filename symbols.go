@@ -0,0 +1,81 @@
+package nvml
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// functions caches the results of HasFunction
+var functions sync.Map
+
+// HasFunction reports whether the loaded NVML library implements the C
+// function with the given symbol name, e.g. "nvmlDeviceModifyDrainState".
+// The package is built against a single version of nvml.h and every wrapper
+// checks for its function before calling it, so programs can use it to tell
+// up front whether the installed driver is new enough.
+func HasFunction(name string) bool {
+	if found, ok := functions.Load(name); ok {
+		return found.(bool)
+	}
+
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	found := C.bridge_has_function(cname) != 0
+	functions.Store(name, found)
+
+	return found
+}
+
+// MissingFunctions returns the functions declared in the vendored nvml.h that
+// the loaded NVML library doesn't implement. Calling their wrappers fails with
+// ErrFunctionNotFound.
+func MissingFunctions() []string {
+	var missing []string
+
+	for _, name := range headerFunctions {
+		if !HasFunction(name) {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
+
+// functionNotFound returns the error of a wrapper around a function the
+// driver doesn't implement, or that was compiled out
+func functionNotFound(name string) error {
+	return &NVMLError{Function: name, Code: int(ReturnFunctionNotFound)}
+}
+
+// lookupFunction returns the address of the function with the given symbol
+// name in the loaded NVML library, or nil if it doesn't implement it
+func lookupFunction(symbol string) unsafe.Pointer {
+	csymbol := C.CString(symbol)
+	defer C.free(unsafe.Pointer(csymbol))
+
+	return C.bridge_function(csymbol)
+}
+
+// requireFunction returns an error matching ErrFunctionNotFound if the loaded
+// NVML library doesn't implement the named function. Every wrapper calls it
+// first, so that older drivers fail softly instead of aborting the process.
+// Functions the header maps to a versioned symbol are looked up by it, while
+// the error names the API function.
+func requireFunction(name string) error {
+	symbol := name
+	if versioned, ok := functionSymbols[name]; ok {
+		symbol = versioned
+	}
+
+	if !HasFunction(symbol) {
+		return functionNotFound(name)
+	}
+
+	return nil
+}
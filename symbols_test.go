@@ -0,0 +1,58 @@
+package nvml
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHasFunction(t *testing.T) {
+	var tests = []struct {
+		name  string
+		found bool
+	}{
+		{"nvmlDeviceGetIndex", true},
+		{"nvmlInit_v2", true},
+		{"nvmlDeviceGetNoSuchThing", false},
+	}
+
+	// The positive cases only hold against a real NVML library, not a stub
+	realLibrary := HasFunction("nvmlInit_v2")
+
+	for _, test := range tests {
+		if test.found && !realLibrary {
+			continue
+		}
+		if found := HasFunction(test.name); found != test.found {
+			t.Errorf("HasFunction(%q) = %v, want %v", test.name, found, test.found)
+		}
+	}
+
+	err := requireFunction("nvmlDeviceGetNoSuchThing")
+	if !errors.Is(err, ErrFunctionNotFound) {
+		t.Errorf("requireFunction of a missing function returned %v", err)
+	}
+	if !realLibrary {
+		t.Skip("NVML library not found")
+	}
+	if err := requireFunction("nvmlDeviceGetIndex"); err != nil {
+		t.Errorf("requireFunction of an existing function returned %v", err)
+	}
+	if err := requireFunction("nvmlInit"); err != nil {
+		t.Errorf("requireFunction of a versioned function returned %v", err)
+	}
+}
+
+func TestMissingGetter(t *testing.T) {
+	intpropfunctions["NoSuchThing"] = &cIntPropFunc{symbol: "nvmlDeviceGetNoSuchThing"}
+	textpropfunctions["NoSuchText"] = &cTextPropFunc{symbol: "nvmlDeviceGetNoSuchText", length: 16}
+	defer delete(intpropfunctions, "NoSuchThing")
+	defer delete(textpropfunctions, "NoSuchText")
+
+	gpu := unreachableDevices(t, 1)[0]
+	if _, err := gpu.intProperty("NoSuchThing"); !errors.Is(err, ErrFunctionNotFound) {
+		t.Errorf("intProperty of a missing getter returned %v", err)
+	}
+	if _, err := gpu.textProperty("NoSuchText"); !errors.Is(err, ErrFunctionNotFound) {
+		t.Errorf("textProperty of a missing getter returned %v", err)
+	}
+}
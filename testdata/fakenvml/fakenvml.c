@@ -0,0 +1,220 @@
+/*
+ * A fake NVML library for running the integration tests without a GPU. It
+ * reports two identical devices of a made-up SKU, "Fake GPU", on a driver
+ * whose version is "fake", and implements only a handful of functions: the
+ * ones the package needs to find the devices, a few getters that succeed,
+ * and a few that fail the way they do on real hardware. Every other function
+ * is missing, as on a driver older than the header, so its wrapper must fail
+ * with ErrFunctionNotFound rather than abort the test.
+ *
+ * Build it with `make fakenvml`, or run the integration tests against it
+ * with `make integration-fake`.
+ */
+
+#include <stdint.h>
+#include <stdio.h>
+#include <string.h>
+
+#include "../../nvml.h"
+
+#define FAKE_DEVICE_COUNT 2
+
+static nvmlReturn_t device_index(nvmlDevice_t device, unsigned int *index)
+{
+    uintptr_t handle = (uintptr_t)device;
+
+    if (handle == 0 || handle > FAKE_DEVICE_COUNT) {
+        return(NVML_ERROR_INVALID_ARGUMENT);
+    }
+
+    *index = (unsigned int)(handle - 1);
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlInit_v2(void)
+{
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlShutdown(void)
+{
+    return(NVML_SUCCESS);
+}
+
+const DECLDIR char *nvmlErrorString(nvmlReturn_t result)
+{
+    switch (result) {
+    case NVML_SUCCESS:
+        return("Success");
+    case NVML_ERROR_INVALID_ARGUMENT:
+        return("Invalid Argument");
+    case NVML_ERROR_NOT_SUPPORTED:
+        return("Not Supported");
+    case NVML_ERROR_INSUFFICIENT_SIZE:
+        return("Insufficient Size");
+    default:
+        return("Unknown Error");
+    }
+}
+
+nvmlReturn_t DECLDIR nvmlSystemGetDriverVersion(char *version, unsigned int length)
+{
+    if (length < sizeof("fake")) {
+        return(NVML_ERROR_INSUFFICIENT_SIZE);
+    }
+
+    strcpy(version, "fake");
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetCount_v2(unsigned int *deviceCount)
+{
+    *deviceCount = FAKE_DEVICE_COUNT;
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetHandleByIndex_v2(unsigned int index, nvmlDevice_t *device)
+{
+    if (index >= FAKE_DEVICE_COUNT) {
+        return(NVML_ERROR_INVALID_ARGUMENT);
+    }
+
+    *device = (nvmlDevice_t)(uintptr_t)(index + 1);
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetIndex(nvmlDevice_t device, unsigned int *index)
+{
+    return(device_index(device, index));
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetMinorNumber(nvmlDevice_t device, unsigned int *minorNumber)
+{
+    return(device_index(device, minorNumber));
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetName(nvmlDevice_t device, char *name, unsigned int length)
+{
+    unsigned int index;
+    nvmlReturn_t ret = device_index(device, &index);
+
+    if (ret != NVML_SUCCESS) {
+        return(ret);
+    }
+
+    snprintf(name, length, "Fake GPU");
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetUUID(nvmlDevice_t device, char *uuid, unsigned int length)
+{
+    unsigned int index;
+    nvmlReturn_t ret = device_index(device, &index);
+
+    if (ret != NVML_SUCCESS) {
+        return(ret);
+    }
+
+    snprintf(uuid, length, "GPU-%08x-0000-0000-0000-000000000000", index);
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetPciInfo_v2(nvmlDevice_t device, nvmlPciInfo_t *pci)
+{
+    unsigned int index;
+    nvmlReturn_t ret = device_index(device, &index);
+
+    if (ret != NVML_SUCCESS) {
+        return(ret);
+    }
+
+    memset(pci, 0, sizeof(*pci));
+    pci->bus = index + 1;
+    pci->pciDeviceId = 0x1eb810de;
+    snprintf(pci->busId, sizeof(pci->busId), "0000:%02x:00.0", pci->bus);
+    return(NVML_SUCCESS);
+}
+
+/* Passively cooled, like most datacenter boards */
+nvmlReturn_t DECLDIR nvmlDeviceGetFanSpeed(nvmlDevice_t device, unsigned int *speed)
+{
+    unsigned int index;
+    nvmlReturn_t ret = device_index(device, &index);
+
+    if (ret != NVML_SUCCESS) {
+        return(ret);
+    }
+
+    return(NVML_ERROR_NOT_SUPPORTED);
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetTemperature(nvmlDevice_t device, nvmlTemperatureSensors_t sensorType, unsigned int *temp)
+{
+    unsigned int index;
+    nvmlReturn_t ret = device_index(device, &index);
+
+    if (ret != NVML_SUCCESS) {
+        return(ret);
+    }
+    if (sensorType != NVML_TEMPERATURE_GPU) {
+        return(NVML_ERROR_INVALID_ARGUMENT);
+    }
+
+    *temp = 40;
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetPerformanceState(nvmlDevice_t device, nvmlPstates_t *pState)
+{
+    unsigned int index;
+    nvmlReturn_t ret = device_index(device, &index);
+
+    if (ret != NVML_SUCCESS) {
+        return(ret);
+    }
+
+    *pState = NVML_PSTATE_8;
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetPowerUsage(nvmlDevice_t device, unsigned int *power)
+{
+    unsigned int index;
+    nvmlReturn_t ret = device_index(device, &index);
+
+    if (ret != NVML_SUCCESS) {
+        return(ret);
+    }
+
+    *power = 30000;
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetMemoryInfo(nvmlDevice_t device, nvmlMemory_t *memory)
+{
+    unsigned int index;
+    nvmlReturn_t ret = device_index(device, &index);
+
+    if (ret != NVML_SUCCESS) {
+        return(ret);
+    }
+
+    memory->total = 16ULL << 30;
+    memory->used = 1ULL << 30;
+    memory->free = memory->total - memory->used;
+    return(NVML_SUCCESS);
+}
+
+nvmlReturn_t DECLDIR nvmlDeviceGetUtilizationRates(nvmlDevice_t device, nvmlUtilization_t *utilization)
+{
+    unsigned int index;
+    nvmlReturn_t ret = device_index(device, &index);
+
+    if (ret != NVML_SUCCESS) {
+        return(ret);
+    }
+
+    utilization->gpu = 0;
+    utilization->memory = 0;
+    return(NVML_SUCCESS);
+}
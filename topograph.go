@@ -0,0 +1,123 @@
+package nvml
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TopologyNode is a GPU in a Topology
+type TopologyNode struct {
+	Index       uint   `json:"index"`
+	UUID        string `json:"uuid"`
+	Name        string `json:"name"`
+	PciBusID    string `json:"pci_bus_id"`
+	CpuAffinity string `json:"cpu_affinity,omitempty"`
+}
+
+// TopologyEdge is the connection between two GPUs in a Topology, identified by
+// their indices
+type TopologyEdge struct {
+	A       uint          `json:"a"`
+	B       uint          `json:"b"`
+	Level   TopologyLevel `json:"level"`
+	NvLinks uint          `json:"nvlinks"`
+	P2PRead P2PStatus     `json:"p2p_read"`
+}
+
+// Topology is the graph of how the GPUs in the system are connected, like the
+// matrix `nvidia-smi topo -m` prints. It encodes to JSON as is.
+type Topology struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// nvLinkPeers counts the active NVLinks of the device by the PCI bus ID of the
+// device on their other end
+func (gpu *Device) nvLinkPeers() map[string]uint {
+	peers := make(map[string]uint)
+
+	for link := uint(0); link < NvLinkMaxLinks; link++ {
+		active, err := gpu.NvLinkState(link)
+		if err != nil || !active {
+			continue
+		}
+
+		remote, err := gpu.NvLinkRemotePciInfo(link)
+		if err == nil {
+			peers[remote.BusID]++
+		}
+	}
+
+	return peers
+}
+
+// BuildTopology walks all GPUs in the system and builds the graph of their
+// PCIe common ancestors, NVLinks and peer-to-peer read support
+func BuildTopology() (*Topology, error) {
+	devices, err := GetAllGPUs()
+	if len(devices) == 0 {
+		return nil, err
+	}
+
+	topology := &Topology{Nodes: []TopologyNode{}, Edges: []TopologyEdge{}}
+	peers := make([]map[string]uint, len(devices))
+
+	for i, gpu := range devices {
+		var node TopologyNode
+		node.Index, _ = gpu.Index()
+		node.UUID, _ = gpu.UUID()
+		node.Name, _ = gpu.Name()
+		if pciinfo, err := gpu.PciInfo(); err == nil {
+			node.PciBusID = pciinfo.BusID
+		}
+		if cpus, err := gpu.CpuAffinity(); err == nil {
+			node.CpuAffinity = cpus.String()
+		}
+
+		topology.Nodes = append(topology.Nodes, node)
+		peers[i] = gpu.nvLinkPeers()
+	}
+
+	for i := range devices {
+		for j := i + 1; j < len(devices); j++ {
+			edge := TopologyEdge{
+				A:       topology.Nodes[i].Index,
+				B:       topology.Nodes[j].Index,
+				Level:   TopologySystem,
+				NvLinks: peers[i][topology.Nodes[j].PciBusID],
+				P2PRead: P2PStatusUnknown,
+			}
+			if level, err := devices[i].TopologyCommonAncestor(devices[j]); err == nil {
+				edge.Level = level
+			}
+			if status, err := devices[i].P2PStatus(devices[j], P2PCapsRead); err == nil {
+				edge.P2PRead = status
+			}
+
+			topology.Edges = append(topology.Edges, edge)
+		}
+	}
+
+	return topology, err
+}
+
+// DOT returns the topology as a Graphviz graph. NVLink edges are drawn bold
+// and labeled with their number of links, PCIe edges with their level.
+func (t *Topology) DOT() string {
+	var b strings.Builder
+
+	b.WriteString("graph topology {\n")
+	for _, node := range t.Nodes {
+		fmt.Fprintf(&b, "\tgpu%d [label=%q];\n", node.Index, fmt.Sprintf("GPU%d %s\n%s", node.Index, node.Name, node.PciBusID))
+	}
+	for _, edge := range t.Edges {
+		if edge.NvLinks > 0 {
+			fmt.Fprintf(&b, "\tgpu%d -- gpu%d [label=\"NV%d\", style=bold];\n", edge.A, edge.B, edge.NvLinks)
+		} else {
+			fmt.Fprintf(&b, "\tgpu%d -- gpu%d [label=%q, style=dashed];\n", edge.A, edge.B, edge.Level.String())
+		}
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package nvml
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var testTopology = &Topology{
+	Nodes: []TopologyNode{
+		{Index: 0, Name: "Tesla V100", PciBusID: "0000:04:00.0"},
+		{Index: 1, Name: "Tesla V100", PciBusID: "0000:05:00.0"},
+	},
+	Edges: []TopologyEdge{
+		{A: 0, B: 1, Level: TopologySingle, NvLinks: 2, P2PRead: P2PStatusOK},
+	},
+}
+
+func TestTopologyDOT(t *testing.T) {
+	expected := "graph topology {\n" +
+		"\tgpu0 [label=\"GPU0 Tesla V100\\n0000:04:00.0\"];\n" +
+		"\tgpu1 [label=\"GPU1 Tesla V100\\n0000:05:00.0\"];\n" +
+		"\tgpu0 -- gpu1 [label=\"NV2\", style=bold];\n" +
+		"}\n"
+
+	if dot := testTopology.DOT(); dot != expected {
+		t.Errorf("DOT() = %s, expected %s", dot, expected)
+	}
+}
+
+func TestTopologyJSON(t *testing.T) {
+	b, err := json.Marshal(testTopology.Edges[0])
+	if err != nil {
+		t.Fatalf("json.Marshal: %s", err)
+	}
+
+	expected := `{"a":0,"b":1,"level":"Single","nvlinks":2,"p2p_read":"OK"}`
+	if string(b) != expected {
+		t.Errorf("json.Marshal = %s, expected %s", b, expected)
+	}
+}
@@ -0,0 +1,351 @@
+package nvml
+
+// See https://docs.nvidia.com/deploy/nvml-api/group__nvmlDeviceQueries.html
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// TopologyLevel is how closely two GPUs are connected through the PCIe tree
+type TopologyLevel int
+
+const (
+	// TopologyInternal GPUs are on the same board, e.g. Tesla K80
+	TopologyInternal TopologyLevel = C.NVML_TOPOLOGY_INTERNAL
+	// TopologySingle GPUs only need to traverse a single PCIe switch
+	TopologySingle TopologyLevel = C.NVML_TOPOLOGY_SINGLE
+	// TopologyMultiple GPUs don't need to traverse a host bridge
+	TopologyMultiple TopologyLevel = C.NVML_TOPOLOGY_MULTIPLE
+	// TopologyHostBridge GPUs are connected to the same host bridge
+	TopologyHostBridge TopologyLevel = C.NVML_TOPOLOGY_HOSTBRIDGE
+	// TopologyCPU GPUs are connected to the same CPU, possibly through
+	// multiple host bridges
+	TopologyCPU TopologyLevel = C.NVML_TOPOLOGY_CPU
+	// TopologySystem includes all GPUs in the system
+	TopologySystem TopologyLevel = C.NVML_TOPOLOGY_SYSTEM
+)
+
+func (l TopologyLevel) String() string {
+	switch l {
+	case TopologyInternal:
+		return "Internal"
+	case TopologySingle:
+		return "Single"
+	case TopologyMultiple:
+		return "Multiple"
+	case TopologyHostBridge:
+		return "HostBridge"
+	case TopologyCPU:
+		return "CPU"
+	case TopologySystem:
+		return "System"
+	}
+
+	return fmt.Sprintf("TopologyLevel(%d)", int(l))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (l TopologyLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// TopologyCommonAncestor returns the closest level of the PCIe tree the device
+// and other have in common
+func (gpu *Device) TopologyCommonAncestor(other *Device) (TopologyLevel, error) {
+	var result C.nvmlReturn_t
+	var clevel C.nvmlGpuTopologyLevel_t
+
+	if err := requireFunction("nvmlDeviceGetTopologyCommonAncestor"); err != nil {
+		return TopologySystem, err
+	}
+
+	result = C.nvmlDeviceGetTopologyCommonAncestor(gpu.handle(), other.handle(), &clevel)
+	if result != C.NVML_SUCCESS {
+		return TopologySystem, newNVMLError("nvmlDeviceGetTopologyCommonAncestor", result)
+	}
+
+	return TopologyLevel(clevel), nil
+}
+
+// devicesFromHandles constructs a Device for each of the given handles. Like
+// GetAllGPUs, it skips devices that fail and returns the others along with
+// the joined errors.
+func devicesFromHandles(cdevices []C.nvmlDevice_t) ([]*Device, error) {
+	var devices []*Device
+	var errs []error
+
+	for i, cdevice := range cdevices {
+		device, err := NewDevice(cdevice)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("device %d: %w", i, err))
+			continue
+		}
+
+		devices = append(devices, device)
+	}
+
+	return devices, errors.Join(errs...)
+}
+
+// TopologyNearestGpus returns the GPUs that are connected to the device at
+// the given topology level or closer. GPUs that can't be queried are left out
+// and reported in the error.
+func (gpu *Device) TopologyNearestGpus(level TopologyLevel) ([]*Device, error) {
+	var result C.nvmlReturn_t
+	var ccount C.uint
+
+	if err := requireFunction("nvmlDeviceGetTopologyNearestGpus"); err != nil {
+		return nil, err
+	}
+
+	result = C.nvmlDeviceGetTopologyNearestGpus(gpu.handle(), C.nvmlGpuTopologyLevel_t(level), &ccount, nil)
+	if result != C.NVML_SUCCESS {
+		return nil, newNVMLError("nvmlDeviceGetTopologyNearestGpus", result)
+	}
+	if ccount == 0 {
+		return nil, nil
+	}
+
+	cdevices := make([]C.nvmlDevice_t, ccount)
+	result = C.nvmlDeviceGetTopologyNearestGpus(gpu.handle(), C.nvmlGpuTopologyLevel_t(level), &ccount, &cdevices[0])
+	if result != C.NVML_SUCCESS {
+		return nil, newNVMLError("nvmlDeviceGetTopologyNearestGpus", result)
+	}
+
+	return devicesFromHandles(cdevices[:ccount])
+}
+
+// SystemTopologyGpuSet returns the GPUs with an affinity to the given CPU. GPUs
+// that can't be queried are left out and reported in the error.
+func SystemTopologyGpuSet(cpuNumber uint) ([]*Device, error) {
+	var result C.nvmlReturn_t
+	var ccount C.uint
+
+	if err := requireFunction("nvmlSystemGetTopologyGpuSet"); err != nil {
+		return nil, err
+	}
+
+	result = C.nvmlSystemGetTopologyGpuSet(C.uint(cpuNumber), &ccount, nil)
+	if result != C.NVML_SUCCESS {
+		return nil, newNVMLError("nvmlSystemGetTopologyGpuSet", result)
+	}
+	if ccount == 0 {
+		return nil, nil
+	}
+
+	cdevices := make([]C.nvmlDevice_t, ccount)
+	result = C.nvmlSystemGetTopologyGpuSet(C.uint(cpuNumber), &ccount, &cdevices[0])
+	if result != C.NVML_SUCCESS {
+		return nil, newNVMLError("nvmlSystemGetTopologyGpuSet", result)
+	}
+
+	return devicesFromHandles(cdevices[:ccount])
+}
+
+// CPUSet is a set of CPU numbers, in ascending order
+type CPUSet []uint
+
+// String formats the set as a Linux cpuset list, e.g. "0-3,32-35"
+func (set CPUSet) String() string {
+	var ranges []string
+
+	for i := 0; i < len(set); {
+		j := i
+		for j+1 < len(set) && set[j+1] == set[j]+1 {
+			j++
+		}
+
+		if i == j {
+			ranges = append(ranges, fmt.Sprintf("%d", set[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", set[i], set[j]))
+		}
+		i = j + 1
+	}
+
+	return strings.Join(ranges, ",")
+}
+
+// Contains reports whether cpu is in the set
+func (set CPUSet) Contains(cpu uint) bool {
+	for _, c := range set {
+		if c == cpu {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cpuSetFromMask converts an array of bitmasks, bitsPerWord CPUs per word, to
+// a CPUSet
+func cpuSetFromMask(mask []uint64, bitsPerWord uint) CPUSet {
+	var set CPUSet
+
+	for i, word := range mask {
+		for bit := uint(0); bit < bitsPerWord; bit++ {
+			if word&(1<<bit) != 0 {
+				set = append(set, uint(i)*bitsPerWord+bit)
+			}
+		}
+	}
+
+	return set
+}
+
+// maxCPUs is the number of CPUs CpuAffinity can report on
+const maxCPUs = 1024
+
+// CpuAffinity returns the CPUs that are ideal for running threads that use the
+// device. Linux only.
+func (gpu *Device) CpuAffinity() (CPUSet, error) {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceGetCpuAffinity"); err != nil {
+		return nil, err
+	}
+
+	bitsPerWord := uint(unsafe.Sizeof(C.ulong(0)) * 8)
+	cmask := make([]C.ulong, maxCPUs/bitsPerWord)
+
+	result = C.nvmlDeviceGetCpuAffinity(gpu.handle(), C.uint(len(cmask)), &cmask[0])
+	if result != C.NVML_SUCCESS {
+		return nil, newNVMLError("nvmlDeviceGetCpuAffinity", result)
+	}
+
+	mask := make([]uint64, len(cmask))
+	for i, word := range cmask {
+		mask[i] = uint64(word)
+	}
+
+	return cpuSetFromMask(mask, bitsPerWord), nil
+}
+
+// SetCpuAffinity binds the calling thread to the CPUs returned by CpuAffinity.
+// Since NVML binds the OS thread, not the goroutine, callers will normally
+// want to call runtime.LockOSThread first. Linux only, up to 64 CPUs.
+func (gpu *Device) SetCpuAffinity() error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceSetCpuAffinity"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceSetCpuAffinity(gpu.handle())
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceSetCpuAffinity", result)
+	}
+
+	return nil
+}
+
+// ClearCpuAffinity clears the CPU affinity bindings of the calling thread.
+// Linux only.
+func (gpu *Device) ClearCpuAffinity() error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlDeviceClearCpuAffinity"); err != nil {
+		return err
+	}
+
+	result = C.nvmlDeviceClearCpuAffinity(gpu.handle())
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlDeviceClearCpuAffinity", result)
+	}
+
+	return nil
+}
+
+// P2PCapsIndex is a peer-to-peer capability that can be checked between two
+// devices
+type P2PCapsIndex int
+
+const (
+	P2PCapsRead    P2PCapsIndex = C.NVML_P2P_CAPS_INDEX_READ
+	P2PCapsWrite   P2PCapsIndex = C.NVML_P2P_CAPS_INDEX_WRITE
+	P2PCapsNvLink  P2PCapsIndex = C.NVML_P2P_CAPS_INDEX_NVLINK
+	P2PCapsAtomics P2PCapsIndex = C.NVML_P2P_CAPS_INDEX_ATOMICS
+	P2PCapsProp    P2PCapsIndex = C.NVML_P2P_CAPS_INDEX_PROP
+)
+
+func (i P2PCapsIndex) String() string {
+	switch i {
+	case P2PCapsRead:
+		return "Read"
+	case P2PCapsWrite:
+		return "Write"
+	case P2PCapsNvLink:
+		return "NvLink"
+	case P2PCapsAtomics:
+		return "Atomics"
+	case P2PCapsProp:
+		return "Prop"
+	}
+
+	return fmt.Sprintf("P2PCapsIndex(%d)", int(i))
+}
+
+// P2PStatus is the status of a peer-to-peer capability between two devices
+type P2PStatus int
+
+const (
+	P2PStatusOK                      P2PStatus = C.NVML_P2P_STATUS_OK
+	P2PStatusChipsetNotSupported     P2PStatus = C.NVML_P2P_STATUS_CHIPSET_NOT_SUPPORED
+	P2PStatusGPUNotSupported         P2PStatus = C.NVML_P2P_STATUS_GPU_NOT_SUPPORTED
+	P2PStatusIOHTopologyNotSupported P2PStatus = C.NVML_P2P_STATUS_IOH_TOPOLOGY_NOT_SUPPORTED
+	P2PStatusDisabledByRegkey        P2PStatus = C.NVML_P2P_STATUS_DISABLED_BY_REGKEY
+	P2PStatusNotSupported            P2PStatus = C.NVML_P2P_STATUS_NOT_SUPPORTED
+	P2PStatusUnknown                 P2PStatus = C.NVML_P2P_STATUS_UNKNOWN
+)
+
+func (s P2PStatus) String() string {
+	switch s {
+	case P2PStatusOK:
+		return "OK"
+	case P2PStatusChipsetNotSupported:
+		return "ChipsetNotSupported"
+	case P2PStatusGPUNotSupported:
+		return "GPUNotSupported"
+	case P2PStatusIOHTopologyNotSupported:
+		return "IOHTopologyNotSupported"
+	case P2PStatusDisabledByRegkey:
+		return "DisabledByRegkey"
+	case P2PStatusNotSupported:
+		return "NotSupported"
+	case P2PStatusUnknown:
+		return "Unknown"
+	}
+
+	return fmt.Sprintf("P2PStatus(%d)", int(s))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s P2PStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// P2PStatus returns whether the given peer-to-peer capability is supported
+// between the device and other, and if not, why
+func (gpu *Device) P2PStatus(other *Device, caps P2PCapsIndex) (P2PStatus, error) {
+	var result C.nvmlReturn_t
+	var cstatus C.nvmlGpuP2PStatus_t
+
+	if err := requireFunction("nvmlDeviceGetP2PStatus"); err != nil {
+		return P2PStatusUnknown, err
+	}
+
+	result = C.nvmlDeviceGetP2PStatus(gpu.handle(), other.handle(), C.nvmlGpuP2PCapsIndex_t(caps), &cstatus)
+	if result != C.NVML_SUCCESS {
+		return P2PStatusUnknown, newNVMLError("nvmlDeviceGetP2PStatus", result)
+	}
+
+	return P2PStatus(cstatus), nil
+}
@@ -0,0 +1,46 @@
+package nvml
+
+import (
+	"testing"
+)
+
+func TestCPUSetFromMask(t *testing.T) {
+	var tests = []struct {
+		mask []uint64
+		s    string
+	}{
+		{[]uint64{0x3, 0x3}, "0-1,64-65"},
+		{[]uint64{0xf0f}, "0-3,8-11"},
+		{[]uint64{0x5}, "0,2"},
+		{[]uint64{0, 0}, ""},
+	}
+
+	for _, ts := range tests {
+		set := cpuSetFromMask(ts.mask, 64)
+		if set.String() != ts.s {
+			t.Errorf("cpuSetFromMask(%v) = %s, expected %s", ts.mask, set.String(), ts.s)
+		}
+	}
+
+	if set := cpuSetFromMask([]uint64{0x3, 0x3}, 32); set.String() != "0-1,32-33" {
+		t.Errorf("cpuSetFromMask with 32 bit words = %s, expected 0-1,32-33", set.String())
+	}
+}
+
+func TestP2PStatusString(t *testing.T) {
+	var tests = []struct {
+		status P2PStatus
+		s      string
+	}{
+		{P2PStatusOK, "OK"},
+		{P2PStatusNotSupported, "NotSupported"},
+		{P2PStatusUnknown, "Unknown"},
+		{P2PStatus(42), "P2PStatus(42)"},
+	}
+
+	for _, ts := range tests {
+		if ts.status.String() != ts.s {
+			t.Errorf("P2PStatus(%d).String() = %s, expected %s", int(ts.status), ts.status.String(), ts.s)
+		}
+	}
+}
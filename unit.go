@@ -0,0 +1,278 @@
+package nvml
+
+// See https://docs.nvidia.com/deploy/nvml-api/group__nvmlUnitQueries.html
+//
+// Units are S-class chassis (e.g. Tesla S1070) housing several GPUs. They are
+// unrelated to the GPUs themselves and most systems have none.
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+// Unit is an S-class chassis
+type Unit struct {
+	nvmlunit C.nvmlUnit_t
+}
+
+// UnitCount returns the number of units in the system
+func UnitCount() (uint, error) {
+	var result C.nvmlReturn_t
+	var ccount C.uint
+
+	if err := requireFunction("nvmlUnitGetCount"); err != nil {
+		return 0, err
+	}
+
+	result = C.nvmlUnitGetCount(&ccount)
+	if result != C.NVML_SUCCESS {
+		return 0, newNVMLError("nvmlUnitGetCount", result)
+	}
+
+	return uint(ccount), nil
+}
+
+// UnitByIndex returns the unit with the given index, from 0 to UnitCount()-1
+func UnitByIndex(index uint) (*Unit, error) {
+	var result C.nvmlReturn_t
+	var cunit C.nvmlUnit_t
+
+	if err := requireFunction("nvmlUnitGetHandleByIndex"); err != nil {
+		return nil, err
+	}
+
+	result = C.nvmlUnitGetHandleByIndex(C.uint(index), &cunit)
+	if result != C.NVML_SUCCESS {
+		return nil, newNVMLError("nvmlUnitGetHandleByIndex", result)
+	}
+
+	return &Unit{nvmlunit: cunit}, nil
+}
+
+// Go correspondent of the C.nvmlUnitInfo_t struct
+type UnitInfo struct {
+	Name            string
+	ID              string
+	Serial          string
+	FirmwareVersion string
+}
+
+// Info returns the static information of the unit
+func (unit *Unit) Info() (UnitInfo, error) {
+	var result C.nvmlReturn_t
+	var cinfo C.nvmlUnitInfo_t
+	var info UnitInfo
+
+	if err := requireFunction("nvmlUnitGetUnitInfo"); err != nil {
+		return info, err
+	}
+
+	result = C.nvmlUnitGetUnitInfo(unit.nvmlunit, &cinfo)
+	if result != C.NVML_SUCCESS {
+		return info, newNVMLError("nvmlUnitGetUnitInfo", result)
+	}
+
+	info.Name = strndup(&cinfo.name[0], uint(len(cinfo.name)))
+	info.ID = strndup(&cinfo.id[0], uint(len(cinfo.id)))
+	info.Serial = strndup(&cinfo.serial[0], uint(len(cinfo.serial)))
+	info.FirmwareVersion = strndup(&cinfo.firmwareVersion[0], uint(len(cinfo.firmwareVersion)))
+
+	return info, nil
+}
+
+// LedState returns whether the unit's LED is green (good health) or amber,
+// and if amber, a description of the cause
+func (unit *Unit) LedState() (amber bool, cause string, err error) {
+	var result C.nvmlReturn_t
+	var cstate C.nvmlLedState_t
+
+	if err := requireFunction("nvmlUnitGetLedState"); err != nil {
+		return false, "", err
+	}
+
+	result = C.nvmlUnitGetLedState(unit.nvmlunit, &cstate)
+	if result != C.NVML_SUCCESS {
+		return false, "", newNVMLError("nvmlUnitGetLedState", result)
+	}
+
+	if cstate.color != C.NVML_LED_COLOR_AMBER {
+		return false, "", nil
+	}
+
+	return true, strndup(&cstate.cause[0], uint(len(cstate.cause))), nil
+}
+
+// Go correspondent of the C.nvmlPSUInfo_t struct
+type PSUInfo struct {
+	State   string
+	Current uint // A
+	Voltage uint // V
+	Power   uint // W
+}
+
+// PsuInfo returns the state of the unit's power supply
+func (unit *Unit) PsuInfo() (PSUInfo, error) {
+	var result C.nvmlReturn_t
+	var cpsu C.nvmlPSUInfo_t
+	var psu PSUInfo
+
+	if err := requireFunction("nvmlUnitGetPsuInfo"); err != nil {
+		return psu, err
+	}
+
+	result = C.nvmlUnitGetPsuInfo(unit.nvmlunit, &cpsu)
+	if result != C.NVML_SUCCESS {
+		return psu, newNVMLError("nvmlUnitGetPsuInfo", result)
+	}
+
+	psu.State = strndup(&cpsu.state[0], uint(len(cpsu.state)))
+	psu.Current = uint(cpsu.current)
+	psu.Voltage = uint(cpsu.voltage)
+	psu.Power = uint(cpsu.power)
+
+	return psu, nil
+}
+
+// UnitTemperatureType is a temperature sensor of a unit
+type UnitTemperatureType uint
+
+const (
+	UnitTemperatureIntake  UnitTemperatureType = 0
+	UnitTemperatureExhaust UnitTemperatureType = 1
+	UnitTemperatureBoard   UnitTemperatureType = 2
+)
+
+func (t UnitTemperatureType) String() string {
+	switch t {
+	case UnitTemperatureIntake:
+		return "Intake"
+	case UnitTemperatureExhaust:
+		return "Exhaust"
+	case UnitTemperatureBoard:
+		return "Board"
+	}
+
+	return fmt.Sprintf("UnitTemperatureType(%d)", int(t))
+}
+
+// Temperature returns the reading of the given sensor, in degrees Celsius. Not
+// every unit has every sensor.
+func (unit *Unit) Temperature(sensor UnitTemperatureType) (uint, error) {
+	var result C.nvmlReturn_t
+	var ctemp C.uint
+
+	if err := requireFunction("nvmlUnitGetTemperature"); err != nil {
+		return 0, err
+	}
+
+	result = C.nvmlUnitGetTemperature(unit.nvmlunit, C.uint(sensor), &ctemp)
+	if result != C.NVML_SUCCESS {
+		return 0, newNVMLError("nvmlUnitGetTemperature", result)
+	}
+
+	return uint(ctemp), nil
+}
+
+// Go correspondent of the C.nvmlUnitFanInfo_t struct
+type UnitFanInfo struct {
+	Speed  uint // RPM
+	Failed bool
+}
+
+// FanSpeedInfo returns the speed and state of each of the unit's fans
+func (unit *Unit) FanSpeedInfo() ([]UnitFanInfo, error) {
+	var result C.nvmlReturn_t
+	var cspeeds C.nvmlUnitFanSpeeds_t
+	var fans []UnitFanInfo
+
+	if err := requireFunction("nvmlUnitGetFanSpeedInfo"); err != nil {
+		return fans, err
+	}
+
+	result = C.nvmlUnitGetFanSpeedInfo(unit.nvmlunit, &cspeeds)
+	if result != C.NVML_SUCCESS {
+		return fans, newNVMLError("nvmlUnitGetFanSpeedInfo", result)
+	}
+
+	for i := 0; i < int(cspeeds.count) && i < len(cspeeds.fans); i++ {
+		fans = append(fans, UnitFanInfo{
+			Speed:  uint(cspeeds.fans[i].speed),
+			Failed: cspeeds.fans[i].state == C.NVML_FAN_FAILED,
+		})
+	}
+
+	return fans, nil
+}
+
+// Devices returns the GPUs housed in the unit. GPUs that can't be queried are
+// left out and reported in the error.
+func (unit *Unit) Devices() ([]*Device, error) {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlUnitGetDevices"); err != nil {
+		return nil, err
+	}
+
+	// A unit can't hold more GPUs than there are in the system
+	count, err := nvmlDeviceGetCount()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	ccount := C.uint(count)
+	cdevices := make([]C.nvmlDevice_t, ccount)
+	result = C.nvmlUnitGetDevices(unit.nvmlunit, &ccount, &cdevices[0])
+	if result != C.NVML_SUCCESS {
+		return nil, newNVMLError("nvmlUnitGetDevices", result)
+	}
+
+	return devicesFromHandles(cdevices[:ccount])
+}
+
+// Go correspondent of the C.nvmlHwbcEntry_t struct
+type HicEntry struct {
+	ID              uint
+	FirmwareVersion string
+}
+
+// SystemHicVersion returns the firmware versions of the host interface cards
+// that connect the system to its S-class units
+func SystemHicVersion() ([]HicEntry, error) {
+	var result C.nvmlReturn_t
+	var ccount C.uint
+	var entries []HicEntry
+
+	if err := requireFunction("nvmlSystemGetHicVersion"); err != nil {
+		return entries, err
+	}
+
+	result = C.nvmlSystemGetHicVersion(&ccount, nil)
+	if result == C.NVML_SUCCESS || (result == C.NVML_ERROR_INSUFFICIENT_SIZE && ccount == 0) {
+		return entries, nil
+	}
+	if result != C.NVML_ERROR_INSUFFICIENT_SIZE {
+		return entries, newNVMLError("nvmlSystemGetHicVersion", result)
+	}
+
+	centries := make([]C.nvmlHwbcEntry_t, ccount)
+	result = C.nvmlSystemGetHicVersion(&ccount, &centries[0])
+	if result != C.NVML_SUCCESS {
+		return entries, newNVMLError("nvmlSystemGetHicVersion", result)
+	}
+
+	for i := range centries[:ccount] {
+		entries = append(entries, HicEntry{
+			ID:              uint(centries[i].hwbcId),
+			FirmwareVersion: strndup(&centries[i].firmwareVersion[0], uint(len(centries[i].firmwareVersion))),
+		})
+	}
+
+	return entries, nil
+}
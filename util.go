@@ -6,21 +6,43 @@ package nvml
 import "C"
 
 import (
-	"errors"
+	"sync"
+	"unsafe"
 )
 
-// NVMLInit initializes the NVML session.
+// initMu serializes NVMLInit and NVMLShutdown
+var initMu sync.Mutex
+
+// NVMLInit initializes the NVML session. NVML reference counts sessions, so it
+// may be called more than once, as long as each call is paired with a call to
+// NVMLShutdown.
 func NVMLInit() error {
 	var result C.nvmlReturn_t
 
+	if err := requireFunction("nvmlInit"); err != nil {
+		return err
+	}
+
+	initMu.Lock()
+	defer initMu.Unlock()
+
 	result = C.nvmlInit()
 	if result != C.NVML_SUCCESS {
-		return errors.New("nvmlInit returned error")
+		return newNVMLError("nvmlInit", result)
 	}
 
 	return nil
 }
 
+// enableState converts a bool to the corresponding nvmlEnableState_t
+func enableState(enabled bool) C.nvmlEnableState_t {
+	if enabled {
+		return C.NVML_FEATURE_ENABLED
+	}
+
+	return C.NVML_FEATURE_DISABLED
+}
+
 // lots of the nvml functions require an allocated *char into which to place
 // strings. genCStringBuffer() allocates this buffer and returns it.
 //
@@ -44,3 +66,39 @@ func genCStringBuffer(size uint) *C.char {
 func strndup(cs *C.char, len uint) string {
 	return C.GoStringN(cs, C.int(C.strnlen(cs, C.size_t(len))))
 }
+
+// NVMLShutdown shuts down the NVML session started by NVMLInit.
+func NVMLShutdown() error {
+	var result C.nvmlReturn_t
+
+	if err := requireFunction("nvmlShutdown"); err != nil {
+		return err
+	}
+
+	initMu.Lock()
+	defer initMu.Unlock()
+
+	result = C.nvmlShutdown()
+	if result != C.NVML_SUCCESS {
+		return newNVMLError("nvmlShutdown", result)
+	}
+
+	return nil
+}
+
+// SystemDriverVersion returns the version of the installed driver, e.g. "384.81"
+func SystemDriverVersion() (string, error) {
+	if err := requireFunction("nvmlSystemGetDriverVersion"); err != nil {
+		return "", err
+	}
+
+	var buf *C.char = genCStringBuffer(C.NVML_SYSTEM_DRIVER_VERSION_BUFFER_SIZE)
+	defer C.free(unsafe.Pointer(buf))
+
+	result := C.nvmlSystemGetDriverVersion(buf, C.NVML_SYSTEM_DRIVER_VERSION_BUFFER_SIZE)
+	if result != C.NVML_SUCCESS {
+		return "", newNVMLError("nvmlSystemGetDriverVersion", result)
+	}
+
+	return strndup(buf, C.NVML_SYSTEM_DRIVER_VERSION_BUFFER_SIZE), nil
+}
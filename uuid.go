@@ -0,0 +1,137 @@
+package nvml
+
+/*
+#include "nvmlbridge.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// GPUUUID is the UUID of a GPU, "GPU-<uuid>", or of a MIG device, either
+// "MIG-<uuid>" or the older "MIG-GPU-<uuid>/<gpu instance>/<compute instance>"
+type GPUUUID struct {
+	uuid string // lowercase, without prefix
+	mig  bool
+
+	// hasInstances is set for the older MIG form, which names the parent GPU
+	// and the instances instead of having a UUID of its own
+	hasInstances    bool
+	gpuInstance     uint
+	computeInstance uint
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// ParseGPUUUID validates and normalizes a GPU or MIG device UUID. The prefix
+// and the hex digits are matched case-insensitively.
+func ParseGPUUUID(s string) (GPUUUID, error) {
+	var id GPUUUID
+	rest := s
+
+	if len(rest) >= 4 && strings.EqualFold(rest[:4], "MIG-") {
+		id.mig = true
+		rest = rest[4:]
+
+		if len(rest) >= 4 && strings.EqualFold(rest[:4], "GPU-") {
+			parts := strings.Split(rest[4:], "/")
+			if len(parts) != 3 {
+				return GPUUUID{}, fmt.Errorf("invalid MIG UUID %q", s)
+			}
+
+			gi, err := strconv.ParseUint(parts[1], 10, 32)
+			if err != nil {
+				return GPUUUID{}, fmt.Errorf("invalid MIG UUID %q", s)
+			}
+			ci, err := strconv.ParseUint(parts[2], 10, 32)
+			if err != nil {
+				return GPUUUID{}, fmt.Errorf("invalid MIG UUID %q", s)
+			}
+
+			id.hasInstances = true
+			id.gpuInstance = uint(gi)
+			id.computeInstance = uint(ci)
+			rest = parts[0]
+		}
+	} else if len(rest) >= 4 && strings.EqualFold(rest[:4], "GPU-") {
+		rest = rest[4:]
+	} else {
+		return GPUUUID{}, fmt.Errorf("invalid GPU UUID %q", s)
+	}
+
+	id.uuid = strings.ToLower(rest)
+	if !uuidPattern.MatchString(id.uuid) {
+		return GPUUUID{}, fmt.Errorf("invalid GPU UUID %q", s)
+	}
+
+	return id, nil
+}
+
+// String formats the UUID in its canonical, lowercase form
+func (id GPUUUID) String() string {
+	switch {
+	case id.hasInstances:
+		return fmt.Sprintf("MIG-GPU-%s/%d/%d", id.uuid, id.gpuInstance, id.computeInstance)
+	case id.mig:
+		return "MIG-" + id.uuid
+	}
+
+	return "GPU-" + id.uuid
+}
+
+// IsMIG reports whether the UUID is that of a MIG device
+func (id GPUUUID) IsMIG() bool {
+	return id.mig
+}
+
+// Instances returns the GPU and compute instance IDs of a MIG UUID in the
+// older "MIG-GPU-<uuid>/<gi>/<ci>" form; ok is false for all other UUIDs
+func (id GPUUUID) Instances() (gpuInstance, computeInstance uint, ok bool) {
+	return id.gpuInstance, id.computeInstance, id.hasInstances
+}
+
+// Parent returns the UUID of the GPU a MIG UUID in the older form belongs to;
+// ok is false for all other UUIDs
+func (id GPUUUID) Parent() (GPUUUID, bool) {
+	if !id.hasInstances {
+		return GPUUUID{}, false
+	}
+
+	return GPUUUID{uuid: id.uuid}, true
+}
+
+// Equal reports whether both UUIDs identify the same device
+func (id GPUUUID) Equal(other GPUUUID) bool {
+	return id == other
+}
+
+// DeviceByUUID returns the GPU with the given UUID. MIG devices aren't
+// supported.
+func DeviceByUUID(id GPUUUID, opts ...DeviceOption) (*Device, error) {
+	var result C.nvmlReturn_t
+	var cdevice C.nvmlDevice_t
+
+	if err := requireFunction("nvmlDeviceGetHandleByUUID"); err != nil {
+		return nil, err
+	}
+
+	if id.IsMIG() {
+		return nil, errors.New("MIG devices are not supported")
+	}
+
+	cuuid := C.CString(id.String())
+	defer C.free(unsafe.Pointer(cuuid))
+
+	result = C.nvmlDeviceGetHandleByUUID(cuuid, &cdevice)
+	if result != C.NVML_SUCCESS {
+		return nil, newNVMLError("nvmlDeviceGetHandleByUUID", result)
+	}
+
+	return NewDevice(cdevice, opts...)
+}
@@ -0,0 +1,57 @@
+package nvml
+
+import (
+	"testing"
+)
+
+func TestParseGPUUUID(t *testing.T) {
+	const uuid = "0a1b2c3d-4e5f-6071-8293-a4b5c6d7e8f9"
+
+	var tests = []struct {
+		in     string
+		out    string
+		mig    bool
+		gi, ci uint
+		inst   bool
+		err    bool
+	}{
+		{"GPU-" + uuid, "GPU-" + uuid, false, 0, 0, false, false},
+		{"gpu-0A1B2C3D-4E5F-6071-8293-A4B5C6D7E8F9", "GPU-" + uuid, false, 0, 0, false, false},
+		{"MIG-" + uuid, "MIG-" + uuid, true, 0, 0, false, false},
+		{"MIG-GPU-" + uuid + "/3/0", "MIG-GPU-" + uuid + "/3/0", true, 3, 0, true, false},
+		{"MIG-GPU-" + uuid + "/3", "", false, 0, 0, false, true},
+		{"MIG-GPU-" + uuid + "/x/0", "", false, 0, 0, false, true},
+		{uuid, "", false, 0, 0, false, true},
+		{"GPU-0a1b2c3d", "", false, 0, 0, false, true},
+		{"GPU-" + uuid + "0", "", false, 0, 0, false, true},
+	}
+
+	for _, ts := range tests {
+		id, err := ParseGPUUUID(ts.in)
+		if (err != nil) != ts.err {
+			t.Errorf("ParseGPUUUID(%q) error = %v", ts.in, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		gi, ci, inst := id.Instances()
+		if id.String() != ts.out || id.IsMIG() != ts.mig || gi != ts.gi || ci != ts.ci || inst != ts.inst {
+			t.Errorf("ParseGPUUUID(%q) = %s (mig %v, instances %d/%d %v)", ts.in, id, id.IsMIG(), gi, ci, inst)
+		}
+	}
+}
+
+func TestGPUUUIDParent(t *testing.T) {
+	mig, _ := ParseGPUUUID("MIG-GPU-0a1b2c3d-4e5f-6071-8293-a4b5c6d7e8f9/1/2")
+	gpu, _ := ParseGPUUUID("GPU-0A1B2C3D-4E5F-6071-8293-A4B5C6D7E8F9")
+
+	parent, ok := mig.Parent()
+	if !ok || !parent.Equal(gpu) {
+		t.Errorf("%s.Parent() = %s, %v, expected %s", mig, parent, ok, gpu)
+	}
+	if _, ok := gpu.Parent(); ok {
+		t.Errorf("%s.Parent() returned ok", gpu)
+	}
+}
@@ -0,0 +1,123 @@
+package nvml
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// VisibleDevices returns the devices that CUDA applications in this process
+// see, in the order CUDA numbers them, by interpreting CUDA_VISIBLE_DEVICES
+// the way the CUDA runtime does: entries are indices or (prefixes of) UUIDs,
+// and the list ends at the first entry that is invalid, unknown or repeated.
+// All devices are visible if the variable is unset.
+//
+// Indices are taken to be in PCI bus order, i.e. CUDA_DEVICE_ORDER=PCI_BUS_ID,
+// since NVML can't tell CUDA's default fastest-first order.
+//
+// MIG devices in the "MIG-GPU-<uuid>/<gpu instance>/<compute instance>" form
+// are resolved to their parent GPU, which is returned once however many of
+// its MIG devices are listed. MIG UUIDs in the newer "MIG-<uuid>" form don't
+// name their parent, and can't be resolved with the vendored NVML API, so
+// they're an error.
+//
+// If some devices couldn't be enumerated, the others are returned along with
+// the error, as in GetAllGPUs.
+func VisibleDevices() ([]*Device, error) {
+	devices, err := GetAllGPUs()
+	if len(devices) == 0 && err != nil {
+		return nil, err
+	}
+
+	value, ok := os.LookupEnv("CUDA_VISIBLE_DEVICES")
+	if !ok {
+		return devices, err
+	}
+
+	visible, verr := visibleDevices(value, devices)
+	return visible, errors.Join(err, verr)
+}
+
+// visibleDevices resolves a CUDA_VISIBLE_DEVICES value against devices, which
+// must have been constructed with their index and UUID
+func visibleDevices(value string, devices []*Device) ([]*Device, error) {
+	var visible []*Device
+	seen := make(map[*Device]bool)
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) >= 4 && strings.EqualFold(entry[:4], "MIG-") {
+			id, err := ParseGPUUUID(entry)
+			if err != nil {
+				break
+			}
+			parent, ok := id.Parent()
+			if !ok {
+				return visible, fmt.Errorf("MIG device %s in CUDA_VISIBLE_DEVICES doesn't name its parent GPU", id)
+			}
+
+			gpu := lookupVisibleDevice(parent.String(), devices)
+			if gpu == nil {
+				break
+			}
+			if !seen[gpu] {
+				seen[gpu] = true
+				visible = append(visible, gpu)
+			}
+			continue
+		}
+
+		gpu := lookupVisibleDevice(entry, devices)
+		if gpu == nil || seen[gpu] {
+			break
+		}
+
+		seen[gpu] = true
+		visible = append(visible, gpu)
+	}
+
+	return visible, nil
+}
+
+// lookupVisibleDevice returns the device an entry of CUDA_VISIBLE_DEVICES
+// refers to, or nil if it refers to none or is ambiguous
+func lookupVisibleDevice(entry string, devices []*Device) *Device {
+	if entry == "" {
+		return nil
+	}
+
+	if index, err := strconv.ParseUint(entry, 10, 32); err == nil {
+		for _, gpu := range devices {
+			gpu.mu.RLock()
+			match := uint64(gpu.index) == index
+			gpu.mu.RUnlock()
+
+			if match {
+				return gpu
+			}
+		}
+		return nil
+	}
+
+	if !strings.HasPrefix(entry, "GPU-") {
+		return nil
+	}
+
+	var found *Device
+	for _, gpu := range devices {
+		gpu.mu.RLock()
+		match := strings.HasPrefix(gpu.uuid, entry)
+		gpu.mu.RUnlock()
+
+		if match {
+			if found != nil {
+				return nil
+			}
+			found = gpu
+		}
+	}
+
+	return found
+}
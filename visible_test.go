@@ -0,0 +1,58 @@
+package nvml
+
+import (
+	"testing"
+)
+
+func TestVisibleDevices(t *testing.T) {
+	devices := []*Device{
+		{index: 0, uuid: "GPU-0a1b2c3d-0000-0000-0000-000000000000"},
+		{index: 1, uuid: "GPU-0a1b9999-0000-0000-0000-000000000000"},
+		{index: 2, uuid: "GPU-ffffffff-0000-0000-0000-000000000000"},
+	}
+
+	var tests = []struct {
+		value   string
+		indices []uint
+		err     bool
+	}{
+		{"", nil, false},
+		{"0,1,2", []uint{0, 1, 2}, false},
+		{"2,0", []uint{2, 0}, false},
+		{"1,5,0", []uint{1}, false},
+		{"0,0,1", []uint{0}, false},
+		{"GPU-ffff,0", []uint{2, 0}, false},
+		{"GPU-0a1b", nil, false},
+		{"1,GPU-0a1b2c3d-0000-0000-0000-000000000000", []uint{1, 0}, false},
+		{"1,foo,0", []uint{1}, false},
+		{"MIG-GPU-0a1b2c3d-0000-0000-0000-000000000000/1/0", []uint{0}, false},
+		{"MIG-GPU-FFFFFFFF-0000-0000-0000-000000000000/1/0,MIG-GPU-ffffffff-0000-0000-0000-000000000000/2/0,1", []uint{2, 1}, false},
+		{"0,MIG-GPU-12345678-0000-0000-0000-000000000000/1/0,1", []uint{0}, false},
+		{"0,MIG-GPU-0a1b/1/0,1", []uint{0}, false},
+		{"1,MIG-0a1b2c3d-0000-0000-0000-000000000000", []uint{1}, true},
+	}
+
+	for _, ts := range tests {
+		visible, err := visibleDevices(ts.value, devices)
+		if (err != nil) != ts.err {
+			t.Errorf("visibleDevices(%q) error = %v", ts.value, err)
+			continue
+		}
+
+		var indices []uint
+		for _, gpu := range visible {
+			indices = append(indices, gpu.index)
+		}
+
+		if len(indices) != len(ts.indices) {
+			t.Errorf("visibleDevices(%q) = %v, expected %v", ts.value, indices, ts.indices)
+			continue
+		}
+		for i := range indices {
+			if indices[i] != ts.indices[i] {
+				t.Errorf("visibleDevices(%q) = %v, expected %v", ts.value, indices, ts.indices)
+				break
+			}
+		}
+	}
+}
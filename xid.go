@@ -0,0 +1,181 @@
+package nvml
+
+// See https://docs.nvidia.com/deploy/xid-errors/index.html
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Xid is the number of an XID error reported by the driver
+type Xid uint64
+
+// A selection of XIDs worth handling explicitly. See the NVIDIA XID
+// documentation for the full list.
+const (
+	XidGraphicsEngineException Xid = 13
+	XidMemoryPageFault         Xid = 31
+	XidCorruptedPushBuffer     Xid = 32
+	XidGPUStoppedProcessing    Xid = 43
+	XidPreemptiveCleanup       Xid = 45
+	XidDoubleBitEccError       Xid = 48
+	XidMicroControllerWarning  Xid = 61
+	XidMicroControllerHalt     Xid = 62
+	XidPageRetirementEvent     Xid = 63
+	XidPageRetirementFailure   Xid = 64
+	XidVideoProcessorException Xid = 68
+	XidNvLinkError             Xid = 74
+	XidFallenOffTheBus         Xid = 79
+	XidHighSingleBitEccRate    Xid = 92
+	XidUnknown                 Xid = 999
+)
+
+// XidCategory is a coarse classification of XIDs by their most likely cause
+type XidCategory int
+
+const (
+	XidCategoryUnknown XidCategory = iota
+	// XidCategoryApplication errors are usually caused by the application
+	// running on the GPU, not by the GPU itself
+	XidCategoryApplication
+	// XidCategoryDriver errors point to a driver problem
+	XidCategoryDriver
+	// XidCategoryMemory errors are memory (ECC) errors
+	XidCategoryMemory
+	// XidCategoryHardware errors usually need the GPU to be reset or replaced
+	XidCategoryHardware
+	// XidCategoryInterconnect errors are NVLink errors
+	XidCategoryInterconnect
+)
+
+func (c XidCategory) String() string {
+	switch c {
+	case XidCategoryApplication:
+		return "Application"
+	case XidCategoryDriver:
+		return "Driver"
+	case XidCategoryMemory:
+		return "Memory"
+	case XidCategoryHardware:
+		return "Hardware"
+	case XidCategoryInterconnect:
+		return "Interconnect"
+	case XidCategoryUnknown:
+		return "Unknown"
+	}
+
+	return fmt.Sprintf("XidCategory(%d)", int(c))
+}
+
+var xidInfo = map[Xid]struct {
+	category    XidCategory
+	description string
+}{
+	XidGraphicsEngineException: {XidCategoryApplication, "Graphics Engine Exception"},
+	XidMemoryPageFault:         {XidCategoryApplication, "GPU memory page fault"},
+	XidCorruptedPushBuffer:     {XidCategoryDriver, "Invalid or corrupted push buffer stream"},
+	XidGPUStoppedProcessing:    {XidCategoryApplication, "GPU stopped processing"},
+	XidPreemptiveCleanup:       {XidCategoryApplication, "Preemptive cleanup, due to previous errors"},
+	XidDoubleBitEccError:       {XidCategoryMemory, "Double Bit ECC Error"},
+	XidMicroControllerWarning:  {XidCategoryHardware, "Internal micro-controller breakpoint/warning"},
+	XidMicroControllerHalt:     {XidCategoryHardware, "Internal micro-controller halt"},
+	XidPageRetirementEvent:     {XidCategoryMemory, "ECC page retirement recording event"},
+	XidPageRetirementFailure:   {XidCategoryMemory, "ECC page retirement recording failure"},
+	XidVideoProcessorException: {XidCategoryHardware, "Video processor exception"},
+	XidNvLinkError:             {XidCategoryInterconnect, "NVLink Error"},
+	XidFallenOffTheBus:         {XidCategoryHardware, "GPU has fallen off the bus"},
+	XidHighSingleBitEccRate:    {XidCategoryMemory, "High single-bit ECC error rate"},
+}
+
+// Category returns the category of the XID, or XidCategoryUnknown for XIDs
+// not documented here
+func (x Xid) Category() XidCategory {
+	return xidInfo[x].category
+}
+
+// Description returns a short description of the XID, or an empty string for
+// XIDs not documented here
+func (x Xid) Description() string {
+	return xidInfo[x].description
+}
+
+func (x Xid) String() string {
+	if info, ok := xidInfo[x]; ok {
+		return fmt.Sprintf("XID %d (%s)", uint64(x), info.description)
+	}
+
+	return fmt.Sprintf("XID %d", uint64(x))
+}
+
+// XidEvent is an XID error that occurred on a device
+type XidEvent struct {
+	Device *Device
+	Xid    Xid
+	Time   time.Time
+}
+
+// WatchXidErrors watches all GPUs on the system for XID errors and sends them
+// on the returned channel until the context is cancelled, after which the
+// channel is closed. Devices that don't support XID events are skipped.
+// NVMLInit must have been called.
+func WatchXidErrors(ctx context.Context) (<-chan XidEvent, error) {
+	// Watch whichever devices could be enumerated
+	devices, err := GetAllGPUs()
+	if len(devices) == 0 && err != nil {
+		return nil, err
+	}
+
+	set, err := NewEventSet()
+	if err != nil {
+		return nil, err
+	}
+
+	var registered int
+	for _, gpu := range devices {
+		supported, err := gpu.SupportedEventTypes()
+		if err != nil || supported&EventTypeXidCriticalError == 0 {
+			continue
+		}
+
+		if err := gpu.RegisterEvents(EventTypeXidCriticalError, set); err == nil {
+			registered++
+		}
+	}
+
+	if registered == 0 {
+		set.Free()
+		return nil, errors.New("no device supports XID events")
+	}
+
+	events := make(chan XidEvent)
+	go func() {
+		defer close(events)
+		defer set.Free()
+
+		for {
+			event, err := set.WaitContext(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				// Waiting fails outright rather than timing out when e.g. a
+				// GPU is lost; don't spin on it.
+				time.Sleep(eventPollIntervalMs * time.Millisecond)
+				continue
+			}
+			if event.EventType != EventTypeXidCriticalError {
+				continue
+			}
+
+			select {
+			case events <- XidEvent{Device: event.Device, Xid: Xid(event.Data), Time: time.Now()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,43 @@
+package nvml
+
+import (
+	"testing"
+)
+
+func TestXidString(t *testing.T) {
+	var tests = []struct {
+		xid      Xid
+		s        string
+		category XidCategory
+	}{
+		{XidDoubleBitEccError, "XID 48 (Double Bit ECC Error)", XidCategoryMemory},
+		{XidFallenOffTheBus, "XID 79 (GPU has fallen off the bus)", XidCategoryHardware},
+		{Xid(12345), "XID 12345", XidCategoryUnknown},
+	}
+
+	for _, ts := range tests {
+		if ts.xid.String() != ts.s {
+			t.Errorf("Xid(%d).String() = %s, expected %s", uint64(ts.xid), ts.xid.String(), ts.s)
+		}
+		if ts.xid.Category() != ts.category {
+			t.Errorf("Xid(%d).Category() = %s, expected %s", uint64(ts.xid), ts.xid.Category(), ts.category)
+		}
+	}
+}
+
+func TestXidCategoryString(t *testing.T) {
+	var tests = []struct {
+		c XidCategory
+		s string
+	}{
+		{XidCategoryMemory, "Memory"},
+		{XidCategoryUnknown, "Unknown"},
+		{XidCategory(100), "XidCategory(100)"},
+	}
+
+	for _, ts := range tests {
+		if ts.c.String() != ts.s {
+			t.Errorf("XidCategory(%d).String() = %s, expected %s", int(ts.c), ts.c.String(), ts.s)
+		}
+	}
+}